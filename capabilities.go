@@ -29,6 +29,15 @@ type TextDocumentClientCapabilities struct {
 	PublishDiagnostics *PublishDiagnosticsClientCapabilities      `json:"publishDiagnostics,omitempty"`
 	FoldingRange       *FoldingRangeClientCapabilites             `json:"foldingRange,omitempty"`
 	SelectionRange     *SelectionRangeClientCapabilities          `json:"selectionRange,omitempty"`
+	SemanticTokens     *SemanticTokensClientCapabilities          `json:"semanticTokens,omitempty"`
+	CallHierarchy      *CallHierarchyClientCapabilities           `json:"callHierarchy,omitempty"`
+	TypeHierarchy      *TypeHierarchyClientCapabilities           `json:"typeHierarchy,omitempty"`
+	InlayHint          *InlayHintClientCapabilities               `json:"inlayHint,omitempty"`
+	Diagnostic         *DiagnosticClientCapabilities              `json:"diagnostic,omitempty"`
+	LinkedEditingRange *LinkedEditingRangeClientCapabilities      `json:"linkedEditingRange,omitempty"`
+	Moniker            *MonikerClientCapabilities                 `json:"moniker,omitempty"`
+	InlineValue        *InlineValueClientCapabilities             `json:"inlineValue,omitempty"`
+	InlineCompletion   *InlineCompletionClientCapabilities        `json:"inlineCompletion,omitempty"`
 }
 
 type WorkspaceClientCapabilities struct {
@@ -40,17 +49,28 @@ type WorkspaceClientCapabilities struct {
 	ExecuteCommand         *ExecuteCommandClientCapabilities         `json:"executeCommand,omitempty"`
 	WorkspaceFolders       bool                                      `json:"workspaceFolders,omitempty"`
 	Configuration          bool                                      `json:"configuration,omitempty"`
+	InlayHint              *InlayHintWorkspaceClientCapabilities     `json:"inlayHint,omitempty"`
+	Diagnostics            *DiagnosticWorkspaceClientCapabilities    `json:"diagnostics,omitempty"`
+	FileOperations         *FileOperationClientCapabilities          `json:"fileOperations,omitempty"`
+	InlineValue            *InlineValueWorkspaceClientCapabilities   `json:"inlineValue,omitempty"`
 }
 
 type WindowClientCapabilities struct {
-	WorkDoneProgress bool `json:"workDoneProgress,omitempty"`
+	WorkDoneProgress bool                            `json:"workDoneProgress,omitempty"`
+	ShowDocument     *ShowDocumentClientCapabilities `json:"showDocument,omitempty"`
+}
+
+type GeneralClientCapabilities struct {
+	PositionEncodings []PositionEncoding `json:"positionEncodings,omitempty"`
 }
 
 type ClientCapabilities struct {
-	Workspace    *WorkspaceClientCapabilities    `json:"workspace,omitempty"`
-	TextDocument *TextDocumentClientCapabilities `json:"textDocument,omitempty"`
-	Window       *WindowClientCapabilities       `json:"window,omitempty"`
-	Experimental interface{}                     `json:"experimental,omitempty"`
+	Workspace        *WorkspaceClientCapabilities        `json:"workspace,omitempty"`
+	TextDocument     *TextDocumentClientCapabilities     `json:"textDocument,omitempty"`
+	Window           *WindowClientCapabilities           `json:"window,omitempty"`
+	NotebookDocument *NotebookDocumentClientCapabilities `json:"notebookDocument,omitempty"`
+	General          *GeneralClientCapabilities          `json:"general,omitempty"`
+	Experimental     interface{}                         `json:"experimental,omitempty"`
 }
 
 type DidChangeConfigurationClientCapabilities struct {
@@ -66,6 +86,12 @@ type WorkspaceSymbolClientCapabilities struct {
 	SymbolKind          *struct {
 		ValueSet []SymbolKind `json:"valueSet,omitempty"`
 	} `json:"symbolKind,omitempty"`
+	TagSupport *struct {
+		ValueSet []SymbolTag `json:"valueSet"`
+	} `json:"tagSupport,omitempty"`
+	ResolveSupport *struct {
+		Properties []string `json:"properties"`
+	} `json:"resolveSupport,omitempty"`
 }
 
 type ExecuteCommandClientCapabilities struct {
@@ -90,6 +116,9 @@ type CompletionClientCapabilities struct {
 		TagSupport              *struct {
 			ValueSet []CompletionItemTag `json:"valueSet,omitempty"`
 		} `json:"tagSupport,omitempty"`
+		ResolveSupport *struct {
+			Properties []string `json:"properties"`
+		} `json:"resolveSupport,omitempty"`
 	} `json:"completionItem,omitempty"`
 	CompletionItemKind *struct {
 		ValueSet []CompletionItemKind `json:"valueSet,omitempty"`
@@ -157,6 +186,10 @@ type CodeActionClientCapabilities struct {
 		} `json:"codeActionKind,omitempty"`
 	} `json:"codeActionLiteralSupport,omitempty"`
 	IsPreferredSupport bool `json:"isPreferredSupport,omitempty"`
+	DataSupport        bool `json:"dataSupport,omitempty"`
+	ResolveSupport     *struct {
+		Properties []string `json:"properties"`
+	} `json:"resolveSupport,omitempty"`
 }
 
 type CodeLensClientCapabilities struct {
@@ -207,33 +240,133 @@ type SelectionRangeClientCapabilities struct {
 	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
 }
 
+type SemanticTokensClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	Requests            struct {
+		Range SemanticTokensRange `json:"range,omitempty"`
+		Full  SemanticTokensFull  `json:"full,omitempty"`
+	} `json:"requests"`
+	TokenTypes              []SemanticTokenType     `json:"tokenTypes"`
+	TokenModifiers          []SemanticTokenModifier `json:"tokenModifiers"`
+	Formats                 []TokenFormat           `json:"formats"`
+	OverlappingTokenSupport bool                    `json:"overlappingTokenSupport,omitempty"`
+	MultilineTokenSupport   bool                    `json:"multilineTokenSupport,omitempty"`
+}
+
+type CallHierarchyClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type TypeHierarchyClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type InlayHintClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	ResolveSupport      *struct {
+		Properties []string `json:"properties"`
+	} `json:"resolveSupport,omitempty"`
+}
+
+type InlayHintWorkspaceClientCapabilities struct {
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
+type DiagnosticClientCapabilities struct {
+	DynamicRegistration    bool `json:"dynamicRegistration,omitempty"`
+	RelatedDocumentSupport bool `json:"relatedDocumentSupport,omitempty"`
+}
+
+type DiagnosticWorkspaceClientCapabilities struct {
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
+type LinkedEditingRangeClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type MonikerClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type InlineValueClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type InlineValueWorkspaceClientCapabilities struct {
+	RefreshSupport bool `json:"refreshSupport,omitempty"`
+}
+
+type InlineCompletionClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+type NotebookDocumentSyncClientCapabilities struct {
+	DynamicRegistration     bool `json:"dynamicRegistration,omitempty"`
+	ExecutionSummarySupport bool `json:"executionSummarySupport,omitempty"`
+}
+
+type NotebookDocumentClientCapabilities struct {
+	Synchronization NotebookDocumentSyncClientCapabilities `json:"synchronization"`
+}
+
+type FileOperationClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	DidCreate           bool `json:"didCreate,omitempty"`
+	WillCreate          bool `json:"willCreate,omitempty"`
+	DidRename           bool `json:"didRename,omitempty"`
+	WillRename          bool `json:"willRename,omitempty"`
+	DidDelete           bool `json:"didDelete,omitempty"`
+	WillDelete          bool `json:"willDelete,omitempty"`
+}
+
+type ShowDocumentClientCapabilities struct {
+	Support bool `json:"support"`
+}
+
 type ServerCapabilities struct {
-	TextDocumentSync                 *TextDocumentSyncOptions           `json:"textDocumentSync,omitempty"`
-	CompletionProvider               *CompletionOptions                 `json:"completionProvider,omitempty"`
-	HoverProvider                    *HoverOptions                      `json:"hoverProvider,omitempty"`
-	SignatureHelpProvider            *SignatureHelpOptions              `json:"signatureHelpProvider,omitempty"`
-	DeclarationProvider              *DeclarationRegistrationOptions    `json:"declarationProvider,omitempty"`
-	DefinitionProvider               *DefinitionOptions                 `json:"definitionProvider,omitempty"`
-	TypeDefinitionProvider           *TypeDefinitionRegistrationOptions `json:"typeDefinitionProvider,omitempty"`
-	ImplementationProvider           *ImplementationRegistrationOptions `json:"implementationProvider,omitempty"`
-	ReferencesProvider               *ReferenceOptions                  `json:"referencesProvider,omitempty"`
-	DocumentHighlightProvider        *DocumentHighlightOptions          `json:"documentHighlightProvider,omitempty"`
-	DocumentSymbolProvider           *DocumentSymbolOptions             `json:"documentSymbolProvider,omitempty"`
-	CodeActionProvider               *CodeActionOptions                 `json:"codeActionProvider,omitempty"`
-	CodeLensProvider                 *CodeLensOptions                   `json:"codeLensProvider,omitempty"`
-	DocumentLinkProvider             *DocumentLinkOptions               `json:"documentLinkProvider,omitempty"`
-	ColorProvider                    *DocumentColorRegistrationOptions  `json:"colorProvider,omitempty"`
-	DocumentFormattingProvider       *DocumentFormattingOptions         `json:"documentFormattingProvider,omitempty"`
-	DocumentRangeFormattingProvider  *DocumentRangeFormattingOptions    `json:"documentRangeFormattingProvider,omitempty"`
-	DocumentOnTypeFormattingProvider *DocumentOnTypeFormattingOptions   `json:"documentOnTypeFormattingProvider,omitempty"`
-	RenameProvider                   *RenameOptions                     `json:"renameProvider,omitempty"`
-	FoldingRangeProvider             *FoldingRangeRegistrationOptions   `json:"foldingRangeProvider,omitempty"`
-	ExecuteCommandProvider           *ExecuteCommandOptions             `json:"executeCommandProvider,omitempty"`
-	WorkspaceSymbolProvder           bool                               `json:"workspaceSymbolProvder,omitempty"`
+	PositionEncoding                 PositionEncoding                       `json:"positionEncoding,omitempty"`
+	TextDocumentSync                 *TextDocumentSyncOptions               `json:"textDocumentSync,omitempty"`
+	NotebookDocumentSync             *NotebookDocumentSyncOptions           `json:"notebookDocumentSync,omitempty"`
+	CompletionProvider               *CompletionOptions                     `json:"completionProvider,omitempty"`
+	HoverProvider                    *HoverOptions                          `json:"hoverProvider,omitempty"`
+	SignatureHelpProvider            *SignatureHelpOptions                  `json:"signatureHelpProvider,omitempty"`
+	DeclarationProvider              *DeclarationRegistrationOptions        `json:"declarationProvider,omitempty"`
+	DefinitionProvider               *DefinitionOptions                     `json:"definitionProvider,omitempty"`
+	TypeDefinitionProvider           *TypeDefinitionRegistrationOptions     `json:"typeDefinitionProvider,omitempty"`
+	ImplementationProvider           *ImplementationRegistrationOptions     `json:"implementationProvider,omitempty"`
+	ReferencesProvider               *ReferenceOptions                      `json:"referencesProvider,omitempty"`
+	DocumentHighlightProvider        *DocumentHighlightOptions              `json:"documentHighlightProvider,omitempty"`
+	DocumentSymbolProvider           *DocumentSymbolOptions                 `json:"documentSymbolProvider,omitempty"`
+	CodeActionProvider               *CodeActionOptions                     `json:"codeActionProvider,omitempty"`
+	CodeLensProvider                 *CodeLensOptions                       `json:"codeLensProvider,omitempty"`
+	DocumentLinkProvider             *DocumentLinkOptions                   `json:"documentLinkProvider,omitempty"`
+	ColorProvider                    *DocumentColorRegistrationOptions      `json:"colorProvider,omitempty"`
+	DocumentFormattingProvider       *DocumentFormattingOptions             `json:"documentFormattingProvider,omitempty"`
+	DocumentRangeFormattingProvider  *DocumentRangeFormattingOptions        `json:"documentRangeFormattingProvider,omitempty"`
+	DocumentOnTypeFormattingProvider *DocumentOnTypeFormattingOptions       `json:"documentOnTypeFormattingProvider,omitempty"`
+	RenameProvider                   *RenameOptions                         `json:"renameProvider,omitempty"`
+	FoldingRangeProvider             *FoldingRangeRegistrationOptions       `json:"foldingRangeProvider,omitempty"`
+	SemanticTokensProvider           *SemanticTokensOptions                 `json:"semanticTokensProvider,omitempty"`
+	CallHierarchyProvider            *CallHierarchyRegistrationOptions      `json:"callHierarchyProvider,omitempty"`
+	TypeHierarchyProvider            *TypeHierarchyRegistrationOptions      `json:"typeHierarchyProvider,omitempty"`
+	InlayHintProvider                *InlayHintRegistrationOptions          `json:"inlayHintProvider,omitempty"`
+	DiagnosticProvider               *DiagnosticRegistrationOptions         `json:"diagnosticProvider,omitempty"`
+	LinkedEditingRangeProvider       *LinkedEditingRangeRegistrationOptions `json:"linkedEditingRangeProvider,omitempty"`
+	MonikerProvider                  *MonikerRegistrationOptions            `json:"monikerProvider,omitempty"`
+	InlineValueProvider              *InlineValueRegistrationOptions        `json:"inlineValueProvider,omitempty"`
+	InlineCompletionProvider         *InlineCompletionOptions               `json:"inlineCompletionProvider,omitempty"`
+	ExecuteCommandProvider           *ExecuteCommandOptions                 `json:"executeCommandProvider,omitempty"`
+	WorkspaceSymbolProvider          *WorkspaceSymbolOptions                `json:"workspaceSymbolProvider,omitempty"`
 	Workspace                        *struct {
 		WorkspaceFolders *WorkspaceFoldersServerCapabilities `json:"workspaceFolders,omitempty"`
+		FileOperations   *FileOperationOptions               `json:"fileOperations,omitempty"`
 	} `json:"workspace,omitempty"`
 	Experimental interface{} `json:"experimental,omitempty"`
+
+	// Deprecated: WorkspaceSymbolProvder is misspelled, use WorkspaceSymbolProvider instead.
+	// It is sent as WorkspaceSymbolProvider unless WorkspaceSymbolProvider is set.
+	WorkspaceSymbolProvder bool `json:"-"`
 }
 
 type WorkspaceFoldersServerCapabilities struct {
@@ -359,6 +492,7 @@ type DocumentSymbolRegistrationOptions struct {
 type CodeActionOptions struct {
 	WorkDoneProgressOptions
 	CodeActionKinds []CodeActionKind `json:"codeActionKinds,omitempty"`
+	ResolveProvider bool             `json:"resolveProvider,omitempty"`
 }
 
 type CodeActionRegistrationOptions struct {
@@ -444,6 +578,134 @@ type FoldingRangeRegistrationOptions struct {
 	StaticRegistrationOptions
 }
 
+type SemanticTokensOptions struct {
+	WorkDoneProgressOptions
+	Legend SemanticTokensLegend `json:"legend"`
+	Range  SemanticTokensRange  `json:"range,omitempty"`
+	Full   SemanticTokensFull   `json:"full,omitempty"`
+}
+
+type SemanticTokensRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	SemanticTokensOptions
+	StaticRegistrationOptions
+}
+
+type CallHierarchyOptions struct {
+	WorkDoneProgressOptions
+}
+
+type CallHierarchyRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	CallHierarchyOptions
+	StaticRegistrationOptions
+}
+
+type TypeHierarchyOptions struct {
+	WorkDoneProgressOptions
+}
+
+type TypeHierarchyRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	TypeHierarchyOptions
+	StaticRegistrationOptions
+}
+
+type InlayHintOptions struct {
+	WorkDoneProgressOptions
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
+type InlayHintRegistrationOptions struct {
+	InlayHintOptions
+	TextDocumentRegistrationOptions
+	StaticRegistrationOptions
+}
+
+type DiagnosticOptions struct {
+	WorkDoneProgressOptions
+	Identifier            string `json:"identifier,omitempty"`
+	InterFileDependencies bool   `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool   `json:"workspaceDiagnostics"`
+}
+
+type DiagnosticRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	DiagnosticOptions
+	StaticRegistrationOptions
+}
+
+type LinkedEditingRangeOptions struct {
+	WorkDoneProgressOptions
+}
+
+type LinkedEditingRangeRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	LinkedEditingRangeOptions
+	StaticRegistrationOptions
+}
+
+type MonikerOptions struct {
+	WorkDoneProgressOptions
+}
+
+type MonikerRegistrationOptions struct {
+	TextDocumentRegistrationOptions
+	MonikerOptions
+}
+
+type InlineValueOptions struct {
+	WorkDoneProgressOptions
+}
+
+type InlineValueRegistrationOptions struct {
+	InlineValueOptions
+	TextDocumentRegistrationOptions
+	StaticRegistrationOptions
+}
+
+type InlineCompletionOptions struct {
+	WorkDoneProgressOptions
+}
+
+type InlineCompletionRegistrationOptions struct {
+	InlineCompletionOptions
+	TextDocumentRegistrationOptions
+	StaticRegistrationOptions
+}
+
+type NotebookDocumentSyncOptions struct {
+	NotebookSelector []NotebookSelector `json:"notebookSelector"`
+	Save             bool               `json:"save,omitempty"`
+}
+
+type NotebookDocumentSyncRegistrationOptions struct {
+	NotebookDocumentSyncOptions
+	StaticRegistrationOptions
+}
+
+type FileOperationRegistrationOptions struct {
+	Filters []FileOperationFilter `json:"filters"`
+}
+
+type FileOperationOptions struct {
+	DidCreate  *FileOperationRegistrationOptions `json:"didCreate,omitempty"`
+	WillCreate *FileOperationRegistrationOptions `json:"willCreate,omitempty"`
+	DidRename  *FileOperationRegistrationOptions `json:"didRename,omitempty"`
+	WillRename *FileOperationRegistrationOptions `json:"willRename,omitempty"`
+	DidDelete  *FileOperationRegistrationOptions `json:"didDelete,omitempty"`
+	WillDelete *FileOperationRegistrationOptions `json:"willDelete,omitempty"`
+}
+
+type WorkspaceSymbolOptions struct {
+	WorkDoneProgressOptions
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
+type WorkspaceSymbolRegistrationOptions struct {
+	WorkspaceSymbolOptions
+}
+
 type ExecuteCommandOptions struct {
 	WorkDoneProgressOptions
 	Commands []string `json:"commands,omitempty"`
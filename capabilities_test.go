@@ -208,9 +208,12 @@ func TestClientCapabilities_MarshalUnmarshal(t *testing.T) {
 				Workspace:    &lsp.WorkspaceClientCapabilities{},
 				TextDocument: &lsp.TextDocumentClientCapabilities{},
 				Window:       &lsp.WindowClientCapabilities{},
+				General: &lsp.GeneralClientCapabilities{
+					PositionEncodings: []lsp.PositionEncoding{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF16},
+				},
 				Experimental: float64(1),
 			},
-			json: `{"workspace":{},"textDocument":{},"window":{},"experimental":1}`,
+			json: `{"workspace":{},"textDocument":{},"window":{},"general":{"positionEncodings":["utf-8","utf-16"]},"experimental":1}`,
 		},
 		{
 			goType: lsp.ClientCapabilities{},
@@ -240,3 +243,277 @@ func TestClientCapabilities_MarshalUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestSemanticTokensOptions_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.SemanticTokensOptions
+		json   string
+	}{
+		{
+			goType: lsp.SemanticTokensOptions{
+				Legend: lsp.SemanticTokensLegend{
+					TokenTypes:     []lsp.SemanticTokenType{lsp.SemanticTokenTypeFunction},
+					TokenModifiers: []lsp.SemanticTokenModifier{lsp.SemanticTokenModifierStatic},
+				},
+				Range: true,
+				Full:  lsp.SemanticTokensFullDelta,
+			},
+			json: `{"legend":{"tokenTypes":["function"],"tokenModifiers":["static"]},"range":true,"full":{"delta":true}}`,
+		},
+		{
+			goType: lsp.SemanticTokensOptions{
+				Legend: lsp.SemanticTokensLegend{
+					TokenTypes:     []lsp.SemanticTokenType{},
+					TokenModifiers: []lsp.SemanticTokenModifier{},
+				},
+			},
+			json: `{"legend":{"tokenTypes":[],"tokenModifiers":[]}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.SemanticTokensOptions{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensClientCapabilities_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.SemanticTokensClientCapabilities
+		json   string
+	}{
+		{
+			goType: func() lsp.SemanticTokensClientCapabilities {
+				c := lsp.SemanticTokensClientCapabilities{
+					TokenTypes:     []lsp.SemanticTokenType{lsp.SemanticTokenTypeClass},
+					TokenModifiers: []lsp.SemanticTokenModifier{lsp.SemanticTokenModifierDeprecated},
+					Formats:        []lsp.TokenFormat{lsp.TokenFormatRelative},
+				}
+				c.Requests.Range = true
+				c.Requests.Full = lsp.SemanticTokensFullSupported
+				return c
+			}(),
+			json: `{"requests":{"range":true,"full":true},"tokenTypes":["class"],"tokenModifiers":["deprecated"],"formats":["relative"]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.SemanticTokensClientCapabilities{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlayHintOptions_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.InlayHintOptions
+		json   string
+	}{
+		{
+			goType: lsp.InlayHintOptions{ResolveProvider: true},
+			json:   `{"resolveProvider":true}`,
+		},
+		{
+			goType: lsp.InlayHintOptions{},
+			json:   `{}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.InlayHintOptions{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiagnosticOptions_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DiagnosticOptions
+		json   string
+	}{
+		{
+			goType: lsp.DiagnosticOptions{Identifier: "identifier", InterFileDependencies: true, WorkspaceDiagnostics: true},
+			json:   `{"identifier":"identifier","interFileDependencies":true,"workspaceDiagnostics":true}`,
+		},
+		{
+			goType: lsp.DiagnosticOptions{},
+			json:   `{"interFileDependencies":false,"workspaceDiagnostics":false}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.DiagnosticOptions{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotebookDocumentSyncOptions_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.NotebookDocumentSyncOptions
+		json     string
+	}{
+		{
+			goStruct: lsp.NotebookDocumentSyncOptions{
+				NotebookSelector: []lsp.NotebookSelector{
+					{
+						Notebook: &lsp.NotebookDocumentFilter{NotebookType: "jupyter-notebook"},
+						Cells:    []lsp.NotebookCellLanguage{{Language: "python"}},
+					},
+				},
+				Save: true,
+			},
+			json: `{"notebookSelector":[{"notebook":{"notebookType":"jupyter-notebook"},"cells":[{"language":"python"}]}],"save":true}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.NotebookDocumentSyncOptions{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileOperationOptions_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.FileOperationOptions
+		json     string
+	}{
+		{
+			goStruct: lsp.FileOperationOptions{
+				WillRename: &lsp.FileOperationRegistrationOptions{
+					Filters: []lsp.FileOperationFilter{
+						{
+							Scheme: "file",
+							Pattern: lsp.FileOperationPattern{
+								Glob:    "**/*.go",
+								Matches: lsp.FileOperationPatternKindFile,
+								Options: &lsp.FileOperationPatternOptions{IgnoreCase: true},
+							},
+						},
+					},
+				},
+			},
+			json: `{"willRename":{"filters":[{"scheme":"file","pattern":{"glob":"**/*.go","matches":"file","options":{"ignoreCase":true}}}]}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.FileOperationOptions{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServerCapabilities_Marshal(t *testing.T) {
+	want, err := json.Marshal(&lsp.ServerCapabilities{})
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+
+	// the deprecated field is not encoded by itself
+	got, err := json.Marshal(&lsp.ServerCapabilities{WorkspaceSymbolProvder: true})
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
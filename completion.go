@@ -0,0 +1,179 @@
+package lsp
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tennashi/lsp/fuzzy"
+)
+
+// CompletionCache keeps the completion candidates of the word being typed,
+// to narrow them down while the client re-requests the incomplete list.
+// The zero value is ready to use.
+type CompletionCache struct {
+	// Encoding is the position encoding used by the positions and the content changes, UTF-16 if empty.
+	Encoding PositionEncoding
+
+	mu      sync.Mutex
+	entries map[DocumentURI]*completionCacheEntry
+}
+
+type completionCacheEntry struct {
+	version   int
+	wordStart Position
+	wordEnd   Position
+	word      string
+	items     []CompletionItem
+}
+
+// Put stores the candidates of the word which begins at wordStart in the version of the document.
+// A document has at most one entry.
+func (c *CompletionCache) Put(uri DocumentURI, version int, wordStart Position, word string, items []CompletionItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[DocumentURI]*completionCacheEntry{}
+	}
+
+	c.entries[uri] = &completionCacheEntry{
+		version:   version,
+		wordStart: wordStart,
+		wordEnd:   c.wordEnd(wordStart, word),
+		word:      word,
+		items:     append([]CompletionItem(nil), items...),
+	}
+}
+
+// Get returns the cached candidates narrowed down to those matching word and ranked by RankCompletionItems.
+// It misses unless the candidates are stored for the version and wordStart, and word extends the stored word.
+func (c *CompletionCache) Get(uri DocumentURI, version int, wordStart Position, word string) ([]CompletionItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[uri]
+	if !ok || e.version != version || e.wordStart != wordStart || !strings.HasPrefix(word, e.word) {
+		return nil, false
+	}
+
+	// the cached items are kept as given not to rank them by the sort texts of the previous ranking
+	items := []CompletionItem{}
+	for _, item := range e.items {
+		filterText := item.FilterText
+		if filterText == "" {
+			filterText = item.Label
+		}
+		if _, ok := fuzzy.Match(word, filterText); ok {
+			items = append(items, item)
+		}
+	}
+	e.word = word
+	e.wordEnd = c.wordEnd(wordStart, word)
+	e.items = items
+
+	return RankCompletionItems(items, word), true
+}
+
+func (c *CompletionCache) Invalidate(uri DocumentURI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, uri)
+}
+
+// Change keeps the entry of the document if all the changes edit the cached word with word characters,
+// invalidates it otherwise.
+func (c *CompletionCache) Change(p DidChangeTextDocumentParams) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uri := p.TextDocument.URI
+	e, ok := c.entries[uri]
+	if !ok {
+		return
+	}
+
+	for _, ch := range p.ContentChanges {
+		if ch.Range == nil || !c.changesWord(e, *ch.Range, ch.Text) {
+			delete(c.entries, uri)
+			return
+		}
+
+		removed := ch.Range.End.Character - ch.Range.Start.Character
+		e.wordEnd.Character += c.units(ch.Text) - removed
+	}
+
+	if p.TextDocument.Version != nil {
+		e.version = *p.TextDocument.Version
+	}
+}
+
+func (c *CompletionCache) changesWord(e *completionCacheEntry, rng Range, text string) bool {
+	if rng.Start.Line != e.wordStart.Line || rng.End.Line != e.wordStart.Line {
+		return false
+	}
+	if comparePosition(rng.Start, e.wordStart) < 0 || comparePosition(rng.End, e.wordEnd) > 0 {
+		return false
+	}
+
+	for _, r := range text {
+		if !isWordRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *CompletionCache) wordEnd(wordStart Position, word string) Position {
+	return Position{
+		Line:      wordStart.Line,
+		Character: wordStart.Character + c.units(word),
+	}
+}
+
+func (c *CompletionCache) units(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		n += codeUnits(r, size, c.Encoding)
+		i += size
+	}
+	return n
+}
+
+// CompletionWord returns the start position and the word characters before pos, which is encoded in enc.
+// The word characters are the letters, the digits and '_'.
+func CompletionWord(text string, pos Position, enc PositionEncoding) (Position, string, error) {
+	end, err := positionOffset(text, pos, enc)
+	if err != nil {
+		return Position{}, "", err
+	}
+
+	start := end
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if !isWordRune(r) {
+			break
+		}
+		start -= size
+	}
+
+	word := text[start:end]
+
+	lineStart, _ := lineOffset(text, pos.Line)
+	n := 0
+	for i := lineStart; i < start; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		n += codeUnits(r, size, enc)
+		i += size
+	}
+
+	return Position{Line: pos.Line, Character: n}, word, nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,210 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func TestCompletionCache(t *testing.T) {
+	items := []lsp.CompletionItem{
+		{Label: "foo"},
+		{Label: "fooBar"},
+		{Label: "bar"},
+	}
+	start := lsp.Position{Line: 1, Character: 4}
+
+	change := func(version int, rng lsp.Range, text string) lsp.DidChangeTextDocumentParams {
+		return lsp.DidChangeTextDocumentParams{
+			TextDocument: lsp.VersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "uri"},
+				Version:                intPtr(version),
+			},
+			ContentChanges: []lsp.TextDocumentContentChangeEvent{
+				{Range: &rng, Text: text},
+			},
+		}
+	}
+
+	cases := []struct {
+		changes []lsp.DidChangeTextDocumentParams
+		version int
+		word    string
+		want    []lsp.CompletionItem
+		ok      bool
+	}{
+		{
+			version: 1,
+			word:    "f",
+			want: []lsp.CompletionItem{
+				{Label: "foo", FilterText: "foo", SortText: "0"},
+				{Label: "fooBar", FilterText: "fooBar", SortText: "1"},
+			},
+			ok: true,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(1, 5, 5), "o"),
+				change(3, lineRange(1, 6, 6), "B"),
+			},
+			version: 3,
+			word:    "foB",
+			want: []lsp.CompletionItem{
+				{Label: "fooBar", FilterText: "fooBar", SortText: "0"},
+			},
+			ok: true,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(1, 5, 5), "o"),
+			},
+			version: 1,
+			word:    "fo",
+			ok:      false,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(1, 5, 5), "."),
+			},
+			version: 2,
+			word:    "f",
+			ok:      false,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(0, 0, 0), "x"),
+			},
+			version: 2,
+			word:    "f",
+			ok:      false,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(1, 6, 6), "o"),
+			},
+			version: 2,
+			word:    "fo",
+			ok:      false,
+		},
+		{
+			changes: []lsp.DidChangeTextDocumentParams{
+				change(2, lineRange(1, 4, 5), ""),
+			},
+			version: 2,
+			word:    "",
+			ok:      false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			c := &lsp.CompletionCache{}
+			c.Put("uri", 1, start, "f", items)
+
+			for _, ch := range tt.changes {
+				c.Change(ch)
+			}
+
+			got, ok := c.Get("uri", tt.version, start, tt.word)
+			if ok != tt.ok {
+				t.Fatalf("ok mismatch: want %v but got %v", tt.ok, ok)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompletionCache_Get(t *testing.T) {
+	c := &lsp.CompletionCache{}
+	c.Put("uri", 1, lsp.Position{}, "", []lsp.CompletionItem{
+		{Label: "f_O", SortText: "b"},
+		{Label: "Foo", SortText: "a"},
+	})
+
+	cases := []struct {
+		word string
+		want []lsp.CompletionItem
+	}{
+		{
+			word: "f",
+			want: []lsp.CompletionItem{
+				{Label: "f_O", FilterText: "f_O", SortText: "0"},
+				{Label: "Foo", FilterText: "Foo", SortText: "1"},
+			},
+		},
+		{
+			// the tie is broken by the given sort texts, not by the previous ranking
+			word: "fo",
+			want: []lsp.CompletionItem{
+				{Label: "Foo", FilterText: "Foo", SortText: "0"},
+				{Label: "f_O", FilterText: "f_O", SortText: "1"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		got, ok := c.Get("uri", 1, lsp.Position{}, tt.word)
+		if !ok {
+			t.Fatalf("should be cached but not")
+		}
+		if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+			t.Fatalf("mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestCompletionCache_Invalidate(t *testing.T) {
+	c := &lsp.CompletionCache{}
+	c.Put("uri", 1, lsp.Position{}, "", []lsp.CompletionItem{{Label: "foo"}})
+	c.Invalidate("uri")
+
+	if _, ok := c.Get("uri", 1, lsp.Position{}, ""); ok {
+		t.Fatalf("should be invalidated but not")
+	}
+}
+
+func TestCompletionWord(t *testing.T) {
+	cases := []struct {
+		text      string
+		pos       lsp.Position
+		wantStart lsp.Position
+		wantWord  string
+	}{
+		{
+			text:      "a.fooBar",
+			pos:       lsp.Position{Line: 0, Character: 6},
+			wantStart: lsp.Position{Line: 0, Character: 2},
+			wantWord:  "fooB",
+		},
+		{
+			text:      "x\n𝒜 é_1",
+			pos:       lsp.Position{Line: 1, Character: 6},
+			wantStart: lsp.Position{Line: 1, Character: 3},
+			wantWord:  "é_1",
+		},
+		{
+			text:      "a.\nb",
+			pos:       lsp.Position{Line: 1, Character: 0},
+			wantStart: lsp.Position{Line: 1, Character: 0},
+			wantWord:  "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			start, word, err := lsp.CompletionWord(tt.text, tt.pos, lsp.PositionEncodingUTF16)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantStart, start, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantWord, word); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,17 +2,20 @@ package lsp
 
 import (
 	"context"
+	"errors"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 type Conn struct {
-	jc *jsonrpc2.Conn
+	jc     *jsonrpc2.Conn
+	server *Server
 }
 
-func wrap(conn *jsonrpc2.Conn) *Conn {
+func wrap(conn *jsonrpc2.Conn, s *Server) *Conn {
 	return &Conn{
-		jc: conn,
+		jc:     conn,
+		server: s,
 	}
 }
 
@@ -36,6 +39,34 @@ func (c *Conn) LogMessage(ctx context.Context, typ MessageType, msg string) erro
 	})
 }
 
+// LogTrace sends the trace of the server execution if the client enables tracing.
+// verbose is sent only if the trace level is verbose.
+func (c *Conn) LogTrace(ctx context.Context, message, verbose string) error {
+	trace := c.server.traceConfig()
+	if trace == TraceConfigOff {
+		return nil
+	}
+	if trace != TraceConfigVerbose {
+		verbose = ""
+	}
+
+	return c.jc.Notify(ctx, "$/logTrace", &LogTraceParams{
+		Message: message,
+		Verbose: verbose,
+	})
+}
+
+// PositionEncoding returns the position encoding negotiated with the client at initialize.
+// The positions the handlers return, such as those by ComputeEditsEncoding, should be encoded in it.
+func (c *Conn) PositionEncoding() PositionEncoding {
+	enc, ok := c.server.positionEncoding.Load().(PositionEncoding)
+	if !ok {
+		return PositionEncodingUTF16
+	}
+
+	return enc
+}
+
 func (c *Conn) ShowMessageRequest(
 	ctx context.Context,
 	typ MessageType,
@@ -60,6 +91,22 @@ func (c *Conn) ShowMessageRequest(
 	return res, nil
 }
 
+// ShowDocument asks the client to show the document, which may be an external resource such as a web page.
+// It fails if the client does not support window/showDocument.
+func (c *Conn) ShowDocument(ctx context.Context, p ShowDocumentParams) (bool, error) {
+	caps := c.server.clientCapabilities.Window
+	if caps == nil || caps.ShowDocument == nil || !caps.ShowDocument.Support {
+		return false, errors.New("window/showDocument not supported by the client")
+	}
+
+	res := ShowDocumentResult{}
+	if err := c.jc.Call(ctx, "window/showDocument", &p, &res); err != nil {
+		return false, err
+	}
+
+	return res.Success, nil
+}
+
 func (c *Conn) WorkDoneProgressCreate(ctx context.Context, token ProgressToken) error {
 	return c.jc.Call(ctx, "window/workDoneProgress/create", struct {
 		Token ProgressToken
@@ -111,3 +158,57 @@ func (c *Conn) Configuration(ctx context.Context, items []ConfigurationItem) ([]
 
 	return res, nil
 }
+
+func (c *Conn) ApplyEdit(ctx context.Context, label string, edit WorkspaceEdit) (ApplyWorkspaceEditResponse, error) {
+	res := ApplyWorkspaceEditResponse{}
+
+	if err := c.jc.Call(ctx, "workspace/applyEdit", &ApplyWorkspaceEditParams{
+		Label: label,
+		Edit:  edit,
+	}, &res); err != nil {
+		return ApplyWorkspaceEditResponse{}, err
+	}
+
+	return res, nil
+}
+
+// RefreshInlayHints asks the client to refresh the inlay hints.
+// It fails if the client does not support workspace/inlayHint/refresh.
+func (c *Conn) RefreshInlayHints(ctx context.Context) error {
+	caps := c.server.clientCapabilities.Workspace
+	if caps == nil || caps.InlayHint == nil || !caps.InlayHint.RefreshSupport {
+		return errors.New("workspace/inlayHint/refresh not supported by the client")
+	}
+
+	return c.jc.Call(ctx, "workspace/inlayHint/refresh", nil, nil)
+}
+
+// Progress reports the progress of token, which is a work done progress or a partial result.
+func (c *Conn) Progress(ctx context.Context, token ProgressToken, value interface{}) error {
+	return c.jc.Notify(ctx, "$/progress", &ProgressParams{
+		Token: token,
+		Value: value,
+	})
+}
+
+// RefreshDiagnostics asks the client to pull the diagnostics again.
+// It fails if the client does not support workspace/diagnostic/refresh.
+func (c *Conn) RefreshDiagnostics(ctx context.Context) error {
+	caps := c.server.clientCapabilities.Workspace
+	if caps == nil || caps.Diagnostics == nil || !caps.Diagnostics.RefreshSupport {
+		return errors.New("workspace/diagnostic/refresh not supported by the client")
+	}
+
+	return c.jc.Call(ctx, "workspace/diagnostic/refresh", nil, nil)
+}
+
+// RefreshInlineValues asks the client to refresh the inline values.
+// It fails if the client does not support workspace/inlineValue/refresh.
+func (c *Conn) RefreshInlineValues(ctx context.Context) error {
+	caps := c.server.clientCapabilities.Workspace
+	if caps == nil || caps.InlineValue == nil || !caps.InlineValue.RefreshSupport {
+		return errors.New("workspace/inlineValue/refresh not supported by the client")
+	}
+
+	return c.jc.Call(ctx, "workspace/inlineValue/refresh", nil, nil)
+}
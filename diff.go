@@ -0,0 +1,222 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ComputeEdits returns the TextEdits which turn old into new, with the positions encoded in UTF-16.
+// The handlers should use ComputeEditsEncoding with Conn.PositionEncoding unless UTF-16 is negotiated.
+func ComputeEdits(old, new string) []TextEdit {
+	return ComputeEditsEncoding(old, new, PositionEncodingUTF16)
+}
+
+// ComputeEditsEncoding returns the TextEdits which turn old into new, with the positions encoded in enc.
+// The lines are diffed first, then each changed block is narrowed to the changed characters.
+func ComputeEditsEncoding(old, new string, enc PositionEncoding) []TextEdit {
+	a := splitLines(old)
+	b := splitLines(new)
+
+	// byte offsets of the beginning of each line in old
+	offsets := make([]int, len(a)+1)
+	for i, l := range a {
+		offsets[i+1] = offsets[i] + len(l)
+	}
+
+	edits := []TextEdit{}
+	for _, h := range diffLines(a, b) {
+		oldText := old[offsets[h.a1]:offsets[h.a2]]
+		newText := strings.Join(b[h.b1:h.b2], "")
+
+		start, oldEnd, newEnd := narrow(oldText, newText)
+
+		edits = append(edits, TextEdit{
+			Range: Range{
+				Start: positionAt(oldText, h.a1, start, enc),
+				End:   positionAt(oldText, h.a1, oldEnd, enc),
+			},
+			NewText: newText[start:newEnd],
+		})
+	}
+
+	return edits
+}
+
+// splitLines splits text into lines keeping their line terminators.
+func splitLines(text string) []string {
+	lines := []string{}
+	for len(text) > 0 {
+		i := strings.IndexAny(text, "\r\n")
+		if i < 0 {
+			lines = append(lines, text)
+			break
+		}
+		if strings.HasPrefix(text[i:], "\r\n") {
+			i++
+		}
+		lines = append(lines, text[:i+1])
+		text = text[i+1:]
+	}
+
+	return lines
+}
+
+// narrow trims the common prefix and suffix of a and b without splitting a rune nor a "\r\n".
+// It returns the start offset and the end offsets in a and b of the differing part.
+func narrow(a, b string) (int, int, int) {
+	start := 0
+	for start < len(a) && start < len(b) {
+		ra, size := utf8.DecodeRuneInString(a[start:])
+		rb, _ := utf8.DecodeRuneInString(b[start:])
+		if ra != rb {
+			break
+		}
+		start += size
+	}
+	if start > 0 && start < len(a) && a[start-1] == '\r' && a[start] == '\n' {
+		start--
+	}
+
+	aEnd, bEnd := len(a), len(b)
+	for aEnd > start && bEnd > start {
+		ra, size := utf8.DecodeLastRuneInString(a[:aEnd])
+		rb, _ := utf8.DecodeLastRuneInString(b[:bEnd])
+		if ra != rb {
+			break
+		}
+		aEnd -= size
+		bEnd -= size
+	}
+	if aEnd > 0 && aEnd < len(a) && a[aEnd-1] == '\r' && a[aEnd] == '\n' {
+		aEnd++
+		bEnd++
+	}
+
+	return start, aEnd, bEnd
+}
+
+// positionAt returns the position of the offset in text, which begins at the line.
+func positionAt(text string, line, offset int, enc PositionEncoding) Position {
+	pos := Position{Line: line}
+	for i := 0; i < offset; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\r' && strings.HasPrefix(text[i:], "\r\n"):
+			pos.Line++
+			pos.Character = 0
+			size = 2
+		case r == '\r' || r == '\n':
+			pos.Line++
+			pos.Character = 0
+		default:
+			pos.Character += codeUnits(r, size, enc)
+		}
+		i += size
+	}
+
+	return pos
+}
+
+type hunk struct {
+	a1, a2 int
+	b1, b2 int
+}
+
+// diffLines returns the blocks which differ between a and b using the Myers' algorithm.
+func diffLines(a, b []string) []hunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	a = a[prefix : len(a)-suffix]
+	b = b[prefix : len(b)-suffix]
+	n, m := len(a), len(b)
+
+	hunks := []hunk{}
+	add := func(a1, a2, b1, b2 int) {
+		if a1 == a2 && b1 == b2 {
+			return
+		}
+		hunks = append(hunks, hunk{a1: a1 + prefix, a2: a2 + prefix, b1: b1 + prefix, b2: b2 + prefix})
+	}
+
+	if n == 0 || m == 0 {
+		add(0, n, 0, m)
+		return hunks
+	}
+
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+	trace := [][]int{}
+
+	d := 0
+loop:
+	for ; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			x := 0
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break loop
+			}
+		}
+	}
+
+	// the common lines found by tracing back the path, from the end
+	type match struct{ x, y int }
+	matches := []match{}
+
+	x, y := n, m
+	for ; d > 0; d-- {
+		tv := trace[d]
+		at := func(k int) int { return tv[k+d] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			matches = append(matches, match{x, y})
+		}
+		// step back over the insertion of b[prevY] or the deletion of a[prevX]
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		matches = append(matches, match{x, y})
+	}
+
+	i, j := 0, 0
+	for l := len(matches) - 1; l >= 0; l-- {
+		mt := matches[l]
+		add(i, mt.x, j, mt.y)
+		i, j = mt.x+1, mt.y+1
+	}
+	add(i, n, j, m)
+
+	return hunks
+}
@@ -0,0 +1,115 @@
+package lsp_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func TestComputeEdits(t *testing.T) {
+	cases := []struct {
+		old  string
+		new  string
+		want []lsp.TextEdit
+	}{
+		{
+			old:  "same\n",
+			new:  "same\n",
+			want: []lsp.TextEdit{},
+		},
+		{
+			old: "a\nb\nc\n",
+			new: "a\nB\nc\n",
+			want: []lsp.TextEdit{
+				{Range: lineRange(1, 0, 1), NewText: "B"},
+			},
+		},
+		{
+			old: "func main() {\n\tfoo( )\n}\n",
+			new: "func main() {\n\tfoo()\n}\n",
+			want: []lsp.TextEdit{
+				{Range: lineRange(1, 5, 6), NewText: ""},
+			},
+		},
+		{
+			old: "a\nc\n",
+			new: "a\nb\nc\n",
+			want: []lsp.TextEdit{
+				{Range: lineRange(1, 0, 0), NewText: "b\n"},
+			},
+		},
+		{
+			old: "😀a\n",
+			new: "😀b\n",
+			want: []lsp.TextEdit{
+				{Range: lineRange(0, 2, 3), NewText: "b"},
+			},
+		},
+		{
+			old: "a",
+			new: "a\n",
+			want: []lsp.TextEdit{
+				{Range: lineRange(0, 1, 1), NewText: "\n"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.ComputeEdits(tt.old, tt.new)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestComputeEditsEncoding_RoundTrip(t *testing.T) {
+	cases := []struct {
+		old string
+		new string
+	}{
+		{old: "", new: "hello\n"},
+		{old: "hello\n", new: ""},
+		{old: "a\r\nb\r\n", new: "a\rb\r\n"},
+		{old: "a\r\nb", new: "a\nb"},
+		{old: "a\rb", new: "a\r\nb"},
+		{old: "x😀y\nz", new: "x😁y\nz\n"},
+		{old: "one\ntwo\nthree\nfour\n", new: "zero\none\nthree\n4\nfive\n"},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	pieces := []string{"a", "b", "\n", "\r\n", "\r", "😀", "é", "   "}
+	random := func() string {
+		var b strings.Builder
+		for i := r.Intn(30); i > 0; i-- {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+		return b.String()
+	}
+	for i := 0; i < 200; i++ {
+		cases = append(cases, struct {
+			old string
+			new string
+		}{old: random(), new: random()})
+	}
+
+	encs := []lsp.PositionEncoding{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF16, lsp.PositionEncodingUTF32}
+
+	for _, tt := range cases {
+		for _, enc := range encs {
+			edits := lsp.ComputeEditsEncoding(tt.old, tt.new, enc)
+
+			got, err := lsp.ApplyTextEdits(tt.old, edits, enc)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.new, got); diff != "" {
+				t.Fatalf("%q -> %q (%s) mismatch (-want +got):\n%s", tt.old, tt.new, enc, diff)
+			}
+		}
+	}
+}
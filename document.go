@@ -0,0 +1,191 @@
+package lsp
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TextDocumentStore keeps the content of the text documents opened by the client.
+// The zero value is ready to use.
+type TextDocumentStore struct {
+	// Encoding is the position encoding used by the content changes, UTF-16 if empty.
+	Encoding PositionEncoding
+
+	mu   sync.RWMutex
+	docs map[DocumentURI]TextDocumentItem
+}
+
+func (s *TextDocumentStore) Open(item TextDocumentItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.docs == nil {
+		s.docs = map[DocumentURI]TextDocumentItem{}
+	}
+	s.docs[item.URI] = item
+}
+
+func (s *TextDocumentStore) Change(p DidChangeTextDocumentParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return errors.New("document not opened")
+	}
+
+	doc, err := applyContentChanges(doc, p, s.Encoding)
+	if err != nil {
+		return err
+	}
+	s.docs[doc.URI] = doc
+
+	return nil
+}
+
+// applyContentChanges returns doc changed by p, whose positions are encoded in enc.
+func applyContentChanges(doc TextDocumentItem, p DidChangeTextDocumentParams, enc PositionEncoding) (TextDocumentItem, error) {
+	text := doc.Text
+	for _, c := range p.ContentChanges {
+		if c.Range == nil {
+			text = c.Text
+			continue
+		}
+
+		start, end, err := rangeOffsets(text, *c.Range, enc)
+		if err != nil {
+			return TextDocumentItem{}, err
+		}
+		text = text[:start] + c.Text + text[end:]
+	}
+
+	doc.Text = text
+	if p.TextDocument.Version != nil {
+		doc.Version = *p.TextDocument.Version
+	}
+
+	return doc, nil
+}
+
+func (s *TextDocumentStore) Close(uri DocumentURI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.docs, uri)
+}
+
+func (s *TextDocumentStore) Get(uri DocumentURI) (TextDocumentItem, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	doc, ok := s.docs[uri]
+	return doc, ok
+}
+
+func (s *TextDocumentStore) Version(uri DocumentURI) (int, bool) {
+	doc, ok := s.Get(uri)
+	if !ok {
+		return 0, false
+	}
+
+	return doc.Version, true
+}
+
+func comparePosition(a, b Position) int {
+	switch {
+	case a.Line < b.Line:
+		return -1
+	case a.Line > b.Line:
+		return 1
+	case a.Character < b.Character:
+		return -1
+	case a.Character > b.Character:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// lineOffset returns the byte offset of the beginning of the line.
+// '\n', '\r\n' and '\r' are all treated as the end of line.
+func lineOffset(text string, line int) (int, bool) {
+	off := 0
+	for l := 0; l < line; l++ {
+		i := strings.IndexAny(text[off:], "\r\n")
+		if i < 0 {
+			return 0, false
+		}
+		off += i
+		if strings.HasPrefix(text[off:], "\r\n") {
+			off += 2
+		} else {
+			off++
+		}
+	}
+
+	return off, true
+}
+
+func positionOffset(text string, pos Position, enc PositionEncoding) (int, error) {
+	if pos.Line < 0 || pos.Character < 0 {
+		return 0, errors.New("invalid position")
+	}
+
+	start, ok := lineOffset(text, pos.Line)
+	if !ok {
+		return 0, errors.New("line out of range")
+	}
+
+	end := len(text)
+	if i := strings.IndexAny(text[start:], "\r\n"); i >= 0 {
+		end = start + i
+	}
+
+	n := 0
+	for i := start; i < end; {
+		if n >= pos.Character {
+			return i, nil
+		}
+		r, size := utf8.DecodeRuneInString(text[i:end])
+		n += codeUnits(r, size, enc)
+		i += size
+	}
+
+	// the character value greater than the line length defaults back to the line length
+	return end, nil
+}
+
+func rangeOffsets(text string, rng Range, enc PositionEncoding) (int, int, error) {
+	if comparePosition(rng.Start, rng.End) > 0 {
+		return 0, 0, errors.New("invalid range")
+	}
+
+	start, err := positionOffset(text, rng.Start, enc)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := positionOffset(text, rng.End, enc)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
+
+// codeUnits returns the number of the code units to encode r, which takes size bytes in UTF-8, in enc.
+func codeUnits(r rune, size int, enc PositionEncoding) int {
+	switch enc {
+	case PositionEncodingUTF8:
+		return size
+	case PositionEncodingUTF32:
+		return 1
+	default:
+		if r >= 0x10000 && r <= utf8.MaxRune {
+			return 2
+		}
+		return 1
+	}
+}
@@ -0,0 +1,142 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func TestTextDocumentStore_Change(t *testing.T) {
+	cases := []struct {
+		text    string
+		changes []lsp.TextDocumentContentChangeEvent
+		want    string
+		err     bool
+	}{
+		{
+			text: "hello world",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{Text: "full"},
+			},
+			want: "full",
+		},
+		{
+			text: "hello\nworld\n",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 1, Character: 0},
+						End:   lsp.Position{Line: 1, Character: 5},
+					},
+					Text: "gopher",
+				},
+			},
+			want: "hello\ngopher\n",
+		},
+		{
+			text: "hello\r\nworld",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 0, Character: 5},
+						End:   lsp.Position{Line: 1, Character: 0},
+					},
+					Text: " ",
+				},
+			},
+			want: "hello world",
+		},
+		{
+			text: "a😀b",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 0, Character: 1},
+						End:   lsp.Position{Line: 0, Character: 3},
+					},
+					Text: "",
+				},
+			},
+			want: "ab",
+		},
+		{
+			text: "abc",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 0, Character: 1},
+						End:   lsp.Position{Line: 0, Character: 100},
+					},
+					Text: "",
+				},
+			},
+			want: "a",
+		},
+		{
+			text: "abc",
+			changes: []lsp.TextDocumentContentChangeEvent{
+				{
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 3, Character: 0},
+						End:   lsp.Position{Line: 3, Character: 0},
+					},
+					Text: "x",
+				},
+			},
+			want: "abc",
+			err:  true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := lsp.TextDocumentStore{}
+			s.Open(lsp.TextDocumentItem{
+				URI:     "uri",
+				Version: 1,
+				Text:    tt.text,
+			})
+
+			err := s.Change(lsp.DidChangeTextDocumentParams{
+				TextDocument: lsp.VersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "uri"},
+					Version:                intPtr(2),
+				},
+				ContentChanges: tt.changes,
+			})
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+
+			got, ok := s.Get("uri")
+			if !ok {
+				t.Fatalf("document should be opened")
+			}
+			if diff := cmp.Diff(tt.want, got.Text); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTextDocumentStore_Version(t *testing.T) {
+	s := lsp.TextDocumentStore{}
+
+	if _, ok := s.Version("uri"); ok {
+		t.Fatalf("document should not be opened")
+	}
+
+	s.Open(lsp.TextDocumentItem{URI: "uri", Version: 1})
+	if v, ok := s.Version("uri"); !ok || v != 1 {
+		t.Fatalf("version mismatch: want 1, got %v", v)
+	}
+
+	s.Close("uri")
+	if _, ok := s.Version("uri"); ok {
+		t.Fatalf("document should be closed")
+	}
+}
@@ -0,0 +1,372 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WorkspaceEditBuilder builds a WorkspaceEdit while rejecting overlapping text edits.
+type WorkspaceEditBuilder struct {
+	docs  *TextDocumentStore
+	ops   DocumentChanges
+	edits map[DocumentURI][]TextEdit
+}
+
+// NewWorkspaceEditBuilder returns a builder which looks up the document versions in docs.
+// docs may be nil, then the versions are left null.
+func NewWorkspaceEditBuilder(docs *TextDocumentStore) *WorkspaceEditBuilder {
+	return &WorkspaceEditBuilder{
+		docs:  docs,
+		edits: map[DocumentURI][]TextEdit{},
+	}
+}
+
+func (b *WorkspaceEditBuilder) Replace(uri DocumentURI, rng Range, text string) error {
+	if comparePosition(rng.Start, rng.End) > 0 {
+		return fmt.Errorf("invalid range: %v", rng)
+	}
+
+	for _, e := range b.edits[uri] {
+		if rangesOverlap(e.Range, rng) {
+			return fmt.Errorf("overlapping edits in %s: %v and %v", uri, e.Range, rng)
+		}
+	}
+
+	edit := TextEdit{
+		Range:   rng,
+		NewText: text,
+	}
+	b.edits[uri] = append(b.edits[uri], edit)
+
+	if n := len(b.ops); n > 0 {
+		last := b.ops[n-1].TextDocumentEdit
+		if last != nil && last.TextDocument.URI == uri {
+			last.Edits = append(last.Edits, edit)
+			return nil
+		}
+	}
+
+	tde := &TextDocumentEdit{
+		TextDocument: VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: TextDocumentIdentifier{URI: uri},
+		},
+		Edits: []TextEdit{edit},
+	}
+	if b.docs != nil {
+		if v, ok := b.docs.Version(uri); ok {
+			tde.TextDocument.Version = &v
+		}
+	}
+	b.ops = append(b.ops, DocumentChange{TextDocumentEdit: tde})
+
+	return nil
+}
+
+func (b *WorkspaceEditBuilder) Insert(uri DocumentURI, pos Position, text string) error {
+	return b.Replace(uri, Range{Start: pos, End: pos}, text)
+}
+
+func (b *WorkspaceEditBuilder) Delete(uri DocumentURI, rng Range) error {
+	return b.Replace(uri, rng, "")
+}
+
+func (b *WorkspaceEditBuilder) CreateFile(uri DocumentURI, opts *CreateFileOptions) {
+	b.ops = append(b.ops, DocumentChange{
+		CreateFile: &CreateFile{
+			Kind:    "create",
+			URI:     uri,
+			Options: opts,
+		},
+	})
+}
+
+func (b *WorkspaceEditBuilder) RenameFile(oldURI, newURI DocumentURI, opts *RenameFileOptions) {
+	b.ops = append(b.ops, DocumentChange{
+		RenameFile: &RenameFile{
+			Kind:    "rename",
+			OldURI:  oldURI,
+			NewURI:  newURI,
+			Options: opts,
+		},
+	})
+}
+
+func (b *WorkspaceEditBuilder) DeleteFile(uri DocumentURI, opts *DeleteFileOptions) {
+	b.ops = append(b.ops, DocumentChange{
+		DeleteFile: &DeleteFile{
+			Kind:    "delete",
+			URI:     uri,
+			Options: opts,
+		},
+	})
+}
+
+// Build returns the WorkspaceEdit using DocumentChanges if the client supports it, Changes otherwise.
+// It fails if the edit contains resource operations the client does not support.
+func (b *WorkspaceEditBuilder) Build(caps *WorkspaceEditClientCapabilities) (*WorkspaceEdit, error) {
+	if caps == nil || !caps.DocumentChanges {
+		changes := map[DocumentURI][]TextEdit{}
+		for _, op := range b.ops {
+			if op.TextDocumentEdit == nil {
+				return nil, fmt.Errorf("resource operations are not supported by the client")
+			}
+			uri := op.TextDocumentEdit.TextDocument.URI
+			changes[uri] = append(changes[uri], op.TextDocumentEdit.Edits...)
+		}
+
+		return &WorkspaceEdit{Changes: changes}, nil
+	}
+
+	for _, op := range b.ops {
+		var kind ResourceOperationKind
+		switch {
+		case op.CreateFile != nil:
+			kind = ResourceOperationKindCreate
+		case op.RenameFile != nil:
+			kind = ResourceOperationKindRename
+		case op.DeleteFile != nil:
+			kind = ResourceOperationKindDelete
+		default:
+			continue
+		}
+
+		if !supportsResourceOperation(caps, kind) {
+			return nil, fmt.Errorf("resource operation %q is not supported by the client", kind)
+		}
+	}
+
+	// the text document edits are copied as Replace appends to the last one
+	dc := make(DocumentChanges, len(b.ops))
+	for i, op := range b.ops {
+		if op.TextDocumentEdit != nil {
+			tde := *op.TextDocumentEdit
+			tde.Edits = append([]TextEdit{}, tde.Edits...)
+			op = DocumentChange{TextDocumentEdit: &tde}
+		}
+		dc[i] = op
+	}
+
+	return &WorkspaceEdit{DocumentChanges: dc}, nil
+}
+
+func supportsResourceOperation(caps *WorkspaceEditClientCapabilities, kind ResourceOperationKind) bool {
+	for _, k := range caps.ResourceOperations {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// rangesOverlap reports whether the ranges share any character.
+// Two inserts at the same position do not overlap.
+func rangesOverlap(a, b Range) bool {
+	return comparePosition(a.Start, b.End) < 0 && comparePosition(b.Start, a.End) < 0
+}
+
+// ApplyTextEdits applies the edits, whose positions are encoded in enc, to text.
+// Inserts at the same position appear in the order of edits.
+func ApplyTextEdits(text string, edits []TextEdit, enc PositionEncoding) (string, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+
+	spans := make([]span, 0, len(edits))
+	for _, e := range edits {
+		start, end, err := rangeOffsets(text, e.Range, enc)
+		if err != nil {
+			return "", err
+		}
+		spans = append(spans, span{start: start, end: end, text: e.NewText})
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end < spans[j].end
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			return "", fmt.Errorf("overlapping edits")
+		}
+		b.WriteString(text[last:s.start])
+		b.WriteString(s.text)
+		last = s.end
+	}
+	b.WriteString(text[last:])
+
+	return b.String(), nil
+}
+
+// ApplyWorkspaceEdit applies edit to the virtual files and returns the result.
+// A folder exists as long as a file under it exists.
+// files is not modified.
+func ApplyWorkspaceEdit(files map[DocumentURI]string, edit *WorkspaceEdit, enc PositionEncoding) (map[DocumentURI]string, error) {
+	res := make(map[DocumentURI]string, len(files))
+	for uri, text := range files {
+		res[uri] = text
+	}
+
+	if edit == nil {
+		return res, nil
+	}
+
+	if edit.DocumentChanges == nil {
+		for uri, edits := range edit.Changes {
+			if err := applyTextDocumentEdit(res, uri, edits, enc); err != nil {
+				return nil, err
+			}
+		}
+
+		return res, nil
+	}
+
+	for _, c := range edit.DocumentChanges {
+		var err error
+		switch {
+		case c.TextDocumentEdit != nil:
+			err = applyTextDocumentEdit(res, c.TextDocumentEdit.TextDocument.URI, c.TextDocumentEdit.Edits, enc)
+		case c.CreateFile != nil:
+			err = applyCreateFile(res, c.CreateFile)
+		case c.RenameFile != nil:
+			err = applyRenameFile(res, c.RenameFile)
+		case c.DeleteFile != nil:
+			err = applyDeleteFile(res, c.DeleteFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func applyTextDocumentEdit(files map[DocumentURI]string, uri DocumentURI, edits []TextEdit, enc PositionEncoding) error {
+	text, ok := files[uri]
+	if !ok {
+		return fmt.Errorf("%s does not exist", uri)
+	}
+
+	text, err := ApplyTextEdits(text, edits, enc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", uri, err)
+	}
+	files[uri] = text
+
+	return nil
+}
+
+func applyCreateFile(files map[DocumentURI]string, op *CreateFile) error {
+	opts := op.Options
+	if opts == nil {
+		opts = &CreateFileOptions{}
+	}
+
+	if fileExists(files, op.URI) {
+		switch {
+		case opts.Overwrite:
+			removeFiles(files, op.URI)
+		case opts.IgnoreIfExists:
+			return nil
+		default:
+			return fmt.Errorf("%s already exists", op.URI)
+		}
+	}
+	files[op.URI] = ""
+
+	return nil
+}
+
+func applyRenameFile(files map[DocumentURI]string, op *RenameFile) error {
+	opts := op.Options
+	if opts == nil {
+		opts = &RenameFileOptions{}
+	}
+
+	if !fileExists(files, op.OldURI) {
+		return fmt.Errorf("%s does not exist", op.OldURI)
+	}
+
+	if fileExists(files, op.NewURI) {
+		switch {
+		case opts.Overwrite:
+			removeFiles(files, op.NewURI)
+		case opts.IgnoreIfExists:
+			return nil
+		default:
+			return fmt.Errorf("%s already exists", op.NewURI)
+		}
+	}
+
+	moved := map[DocumentURI]string{}
+	for uri, text := range files {
+		switch {
+		case uri == op.OldURI:
+			moved[op.NewURI] = text
+		case isUnder(uri, op.OldURI):
+			rel := strings.TrimPrefix(string(uri), strings.TrimSuffix(string(op.OldURI), "/"))
+			moved[DocumentURI(strings.TrimSuffix(string(op.NewURI), "/")+rel)] = text
+		default:
+			continue
+		}
+		delete(files, uri)
+	}
+	for uri, text := range moved {
+		files[uri] = text
+	}
+
+	return nil
+}
+
+func applyDeleteFile(files map[DocumentURI]string, op *DeleteFile) error {
+	opts := op.Options
+	if opts == nil {
+		opts = &DeleteFileOptions{}
+	}
+
+	if !fileExists(files, op.URI) {
+		if opts.IgnoreIfNotExists {
+			return nil
+		}
+		return fmt.Errorf("%s does not exist", op.URI)
+	}
+
+	if _, ok := files[op.URI]; !ok && !opts.Recursive {
+		return fmt.Errorf("%s is not empty", op.URI)
+	}
+	removeFiles(files, op.URI)
+
+	return nil
+}
+
+func fileExists(files map[DocumentURI]string, uri DocumentURI) bool {
+	if _, ok := files[uri]; ok {
+		return true
+	}
+
+	for u := range files {
+		if isUnder(u, uri) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeFiles(files map[DocumentURI]string, uri DocumentURI) {
+	for u := range files {
+		if u == uri || isUnder(u, uri) {
+			delete(files, u)
+		}
+	}
+}
+
+func isUnder(uri, dir DocumentURI) bool {
+	return strings.HasPrefix(string(uri), strings.TrimSuffix(string(dir), "/")+"/")
+}
@@ -0,0 +1,511 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func lineRange(line, start, end int) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: line, Character: start},
+		End:   lsp.Position{Line: line, Character: end},
+	}
+}
+
+func TestWorkspaceEditBuilder_Replace(t *testing.T) {
+	cases := []struct {
+		ranges []lsp.Range
+		err    bool
+	}{
+		{
+			ranges: []lsp.Range{lineRange(0, 0, 3), lineRange(0, 3, 5)},
+		},
+		{
+			ranges: []lsp.Range{lineRange(0, 0, 0), lineRange(0, 0, 0)},
+		},
+		{
+			ranges: []lsp.Range{lineRange(0, 0, 3), lineRange(0, 0, 0)},
+		},
+		{
+			ranges: []lsp.Range{lineRange(0, 0, 3), lineRange(0, 2, 5)},
+			err:    true,
+		},
+		{
+			ranges: []lsp.Range{lineRange(0, 0, 3), lineRange(0, 1, 1)},
+			err:    true,
+		},
+		{
+			ranges: []lsp.Range{lineRange(0, 3, 1)},
+			err:    true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			b := lsp.NewWorkspaceEditBuilder(nil)
+
+			var err error
+			for _, r := range tt.ranges {
+				if err = b.Replace("uri", r, "text"); err != nil {
+					break
+				}
+			}
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
+func TestWorkspaceEditBuilder_Build(t *testing.T) {
+	docs := &lsp.TextDocumentStore{}
+	docs.Open(lsp.TextDocumentItem{URI: "opened", Version: 3})
+
+	build := func(b *lsp.WorkspaceEditBuilder) {
+		if err := b.Insert("opened", lsp.Position{}, "a"); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+		if err := b.Delete("closed", lineRange(0, 0, 1)); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+		if err := b.Insert("opened", lsp.Position{Line: 1}, "b"); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+	}
+
+	openedEdit := func(edits ...lsp.TextEdit) lsp.DocumentChange {
+		return lsp.DocumentChange{
+			TextDocumentEdit: &lsp.TextDocumentEdit{
+				TextDocument: lsp.VersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "opened"},
+					Version:                intPtr(3),
+				},
+				Edits: edits,
+			},
+		}
+	}
+	closedEdit := lsp.DocumentChange{
+		TextDocumentEdit: &lsp.TextDocumentEdit{
+			TextDocument: lsp.VersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "closed"},
+			},
+			Edits: []lsp.TextEdit{
+				{Range: lineRange(0, 0, 1), NewText: ""},
+			},
+		},
+	}
+
+	cases := []struct {
+		caps      *lsp.WorkspaceEditClientCapabilities
+		resources bool
+		want      *lsp.WorkspaceEdit
+		err       bool
+	}{
+		{
+			caps: nil,
+			want: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"opened": {
+						{Range: lineRange(0, 0, 0), NewText: "a"},
+						{Range: lineRange(1, 0, 0), NewText: "b"},
+					},
+					"closed": {
+						{Range: lineRange(0, 0, 1), NewText: ""},
+					},
+				},
+			},
+		},
+		{
+			caps: &lsp.WorkspaceEditClientCapabilities{DocumentChanges: true},
+			want: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					openedEdit(lsp.TextEdit{Range: lineRange(0, 0, 0), NewText: "a"}),
+					closedEdit,
+					openedEdit(lsp.TextEdit{Range: lineRange(1, 0, 0), NewText: "b"}),
+				},
+			},
+		},
+		{
+			caps:      nil,
+			resources: true,
+			err:       true,
+		},
+		{
+			caps: &lsp.WorkspaceEditClientCapabilities{
+				DocumentChanges:    true,
+				ResourceOperations: []lsp.ResourceOperationKind{lsp.ResourceOperationKindCreate},
+			},
+			resources: true,
+			err:       true,
+		},
+		{
+			caps: &lsp.WorkspaceEditClientCapabilities{
+				DocumentChanges: true,
+				ResourceOperations: []lsp.ResourceOperationKind{
+					lsp.ResourceOperationKindCreate,
+					lsp.ResourceOperationKindRename,
+				},
+			},
+			resources: true,
+			want: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					openedEdit(lsp.TextEdit{Range: lineRange(0, 0, 0), NewText: "a"}),
+					closedEdit,
+					openedEdit(lsp.TextEdit{Range: lineRange(1, 0, 0), NewText: "b"}),
+					{RenameFile: &lsp.RenameFile{Kind: "rename", OldURI: "opened", NewURI: "renamed"}},
+					{
+						TextDocumentEdit: &lsp.TextDocumentEdit{
+							TextDocument: lsp.VersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "renamed"},
+							},
+							Edits: []lsp.TextEdit{
+								{Range: lineRange(0, 0, 0), NewText: "c"},
+							},
+						},
+					},
+					{CreateFile: &lsp.CreateFile{Kind: "create", URI: "new"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			b := lsp.NewWorkspaceEditBuilder(docs)
+			build(b)
+			if tt.resources {
+				b.RenameFile("opened", "renamed", nil)
+				if err := b.Insert("renamed", lsp.Position{}, "c"); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+				b.CreateFile("new", nil)
+			}
+
+			got, err := b.Build(tt.caps)
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceEditBuilder_Build_Copy(t *testing.T) {
+	b := lsp.NewWorkspaceEditBuilder(nil)
+	if err := b.Insert("uri", lsp.Position{}, "a"); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+
+	got, err := b.Build(&lsp.WorkspaceEditClientCapabilities{DocumentChanges: true})
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+
+	// the edits added after Build should not change the returned WorkspaceEdit
+	if err := b.Insert("uri", lsp.Position{Line: 1}, "b"); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+
+	want := &lsp.WorkspaceEdit{
+		DocumentChanges: lsp.DocumentChanges{
+			{
+				TextDocumentEdit: &lsp.TextDocumentEdit{
+					TextDocument: lsp.VersionedTextDocumentIdentifier{
+						TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "uri"},
+					},
+					Edits: []lsp.TextEdit{
+						{Range: lineRange(0, 0, 0), NewText: "a"},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpOpt); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyTextEdits(t *testing.T) {
+	cases := []struct {
+		text  string
+		edits []lsp.TextEdit
+		enc   lsp.PositionEncoding
+		want  string
+		err   bool
+	}{
+		{
+			text: "hello world",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 6, 11), NewText: "gopher"},
+				{Range: lineRange(0, 0, 5), NewText: "hi"},
+			},
+			want: "hi gopher",
+		},
+		{
+			text: "ab",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 1, 1), NewText: "1"},
+				{Range: lineRange(0, 1, 1), NewText: "2"},
+				{Range: lineRange(0, 1, 1), NewText: "3"},
+			},
+			want: "a123b",
+		},
+		{
+			text: "abc",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 1, 2), NewText: "X"},
+				{Range: lineRange(0, 1, 1), NewText: "I"},
+			},
+			want: "aIXc",
+		},
+		{
+			text: "line1\nline2\nline3",
+			edits: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 0, Character: 5},
+						End:   lsp.Position{Line: 2, Character: 0},
+					},
+					NewText: "\n",
+				},
+			},
+			want: "line1\nline3",
+		},
+		{
+			text: "😀x",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 2, 3), NewText: "y"},
+			},
+			enc:  lsp.PositionEncodingUTF16,
+			want: "😀y",
+		},
+		{
+			text: "😀x",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 4, 5), NewText: "y"},
+			},
+			enc:  lsp.PositionEncodingUTF8,
+			want: "😀y",
+		},
+		{
+			text: "😀x",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 1, 2), NewText: "y"},
+			},
+			enc:  lsp.PositionEncodingUTF32,
+			want: "😀y",
+		},
+		{
+			text: "abc",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(0, 0, 2), NewText: "x"},
+				{Range: lineRange(0, 1, 3), NewText: "y"},
+			},
+			err: true,
+		},
+		{
+			text: "abc",
+			edits: []lsp.TextEdit{
+				{Range: lineRange(1, 0, 0), NewText: "x"},
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got, err := lsp.ApplyTextEdits(tt.text, tt.edits, tt.enc)
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyWorkspaceEdit(t *testing.T) {
+	files := map[lsp.DocumentURI]string{
+		"file:///a.go":     "package a",
+		"file:///dir/b.go": "package b",
+		"file:///dir/c.go": "package c",
+	}
+
+	cases := []struct {
+		edit *lsp.WorkspaceEdit
+		want map[lsp.DocumentURI]string
+		err  bool
+	}{
+		{
+			edit: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///a.go": {{Range: lineRange(0, 8, 9), NewText: "x"}},
+				},
+			},
+			want: map[lsp.DocumentURI]string{
+				"file:///a.go":     "package x",
+				"file:///dir/b.go": "package b",
+				"file:///dir/c.go": "package c",
+			},
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{RenameFile: &lsp.RenameFile{OldURI: "file:///a.go", NewURI: "file:///x.go"}},
+					{
+						TextDocumentEdit: &lsp.TextDocumentEdit{
+							TextDocument: lsp.VersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///x.go"},
+							},
+							Edits: []lsp.TextEdit{{Range: lineRange(0, 8, 9), NewText: "x"}},
+						},
+					},
+					{CreateFile: &lsp.CreateFile{URI: "file:///new.go"}},
+					{RenameFile: &lsp.RenameFile{OldURI: "file:///dir", NewURI: "file:///pkg"}},
+				},
+			},
+			want: map[lsp.DocumentURI]string{
+				"file:///x.go":     "package x",
+				"file:///new.go":   "",
+				"file:///pkg/b.go": "package b",
+				"file:///pkg/c.go": "package c",
+			},
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{CreateFile: &lsp.CreateFile{URI: "file:///a.go"}},
+				},
+			},
+			err: true,
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{
+						CreateFile: &lsp.CreateFile{
+							URI:     "file:///a.go",
+							Options: &lsp.CreateFileOptions{IgnoreIfExists: true},
+						},
+					},
+				},
+			},
+			want: files,
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{
+						CreateFile: &lsp.CreateFile{
+							URI:     "file:///a.go",
+							Options: &lsp.CreateFileOptions{Overwrite: true, IgnoreIfExists: true},
+						},
+					},
+				},
+			},
+			want: map[lsp.DocumentURI]string{
+				"file:///a.go":     "",
+				"file:///dir/b.go": "package b",
+				"file:///dir/c.go": "package c",
+			},
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{RenameFile: &lsp.RenameFile{OldURI: "file:///a.go", NewURI: "file:///dir/b.go"}},
+				},
+			},
+			err: true,
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{
+						RenameFile: &lsp.RenameFile{
+							OldURI:  "file:///a.go",
+							NewURI:  "file:///dir/b.go",
+							Options: &lsp.RenameFileOptions{Overwrite: true},
+						},
+					},
+				},
+			},
+			want: map[lsp.DocumentURI]string{
+				"file:///dir/b.go": "package a",
+				"file:///dir/c.go": "package c",
+			},
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{DeleteFile: &lsp.DeleteFile{URI: "file:///dir"}},
+				},
+			},
+			err: true,
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{
+						DeleteFile: &lsp.DeleteFile{
+							URI:     "file:///dir",
+							Options: &lsp.DeleteFileOptions{Recursive: true},
+						},
+					},
+				},
+			},
+			want: map[lsp.DocumentURI]string{
+				"file:///a.go": "package a",
+			},
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{DeleteFile: &lsp.DeleteFile{URI: "file:///none.go"}},
+				},
+			},
+			err: true,
+		},
+		{
+			edit: &lsp.WorkspaceEdit{
+				DocumentChanges: lsp.DocumentChanges{
+					{
+						DeleteFile: &lsp.DeleteFile{
+							URI:     "file:///none.go",
+							Options: &lsp.DeleteFileOptions{IgnoreIfNotExists: true},
+						},
+					},
+				},
+			},
+			want: files,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got, err := lsp.ApplyWorkspaceEdit(files, tt.edit, "")
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package fuzzy matches the text the user typed against the candidates.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Range is the byte offsets of a matched part of a candidate, End exclusive.
+type Range struct {
+	Start int
+	End   int
+}
+
+type Result struct {
+	Score  int
+	Ranges []Range
+}
+
+const (
+	scoreMatch       = 1
+	bonusCase        = 1
+	bonusStart       = 8
+	bonusWordStart   = 5
+	bonusConsecutive = 5
+)
+
+// Match matches pattern against candidate case-insensitively.
+// All the characters of pattern must appear in candidate in order.
+// The matches at the beginnings of the words and the consecutive matches are scored higher.
+// An empty pattern matches any candidate with the score 0.
+func Match(pattern, candidate string) (Result, bool) {
+	if pattern == "" {
+		return Result{}, true
+	}
+
+	pat := []rune(pattern)
+
+	// offsets[j] is the byte offset of cand[j] in candidate
+	cand := []rune{}
+	offsets := []int{}
+	for i, r := range candidate {
+		cand = append(cand, r)
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(candidate))
+
+	m, n := len(pat), len(cand)
+	if m > n {
+		return Result{}, false
+	}
+
+	// score[i][j] is the best score of pat[:i+1] whose last rune matches cand[j], -1 if impossible.
+	score := make([][]int, m)
+	from := make([][]int, m)
+	for i := range score {
+		score[i] = make([]int, n)
+		from[i] = make([]int, n)
+		for j := range score[i] {
+			score[i][j] = -1
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		// the best score of pat[:i] matched before j-1, and where
+		best, bestAt := -1, -1
+		for j := i; j < n; j++ {
+			if i > 0 && j >= 2 && score[i-1][j-2] > best {
+				best, bestAt = score[i-1][j-2], j-2
+			}
+
+			if unicode.ToLower(pat[i]) != unicode.ToLower(cand[j]) {
+				continue
+			}
+
+			s := charScore(pat[i], cand, j)
+			if i == 0 {
+				score[i][j] = s
+				from[i][j] = -1
+				continue
+			}
+
+			if best >= 0 {
+				score[i][j] = best + s
+				from[i][j] = bestAt
+			}
+			if prev := score[i-1][j-1]; prev >= 0 && prev+s+bonusConsecutive > score[i][j] {
+				score[i][j] = prev + s + bonusConsecutive
+				from[i][j] = j - 1
+			}
+		}
+	}
+
+	end := -1
+	for j := m - 1; j < n; j++ {
+		if score[m-1][j] >= 0 && (end < 0 || score[m-1][j] > score[m-1][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return Result{}, false
+	}
+
+	res := Result{Score: score[m-1][end]}
+
+	matched := make([]int, m)
+	for i, j := m-1, end; i >= 0; i-- {
+		matched[i] = j
+		j = from[i][j]
+	}
+	for _, j := range matched {
+		if l := len(res.Ranges); l > 0 && res.Ranges[l-1].End == offsets[j] {
+			res.Ranges[l-1].End = offsets[j+1]
+			continue
+		}
+		res.Ranges = append(res.Ranges, Range{Start: offsets[j], End: offsets[j+1]})
+	}
+
+	return res, true
+}
+
+func charScore(p rune, cand []rune, j int) int {
+	s := scoreMatch
+	if p == cand[j] {
+		s += bonusCase
+	}
+
+	switch {
+	case j == 0:
+		s += bonusStart
+	case isSeparator(cand[j-1]):
+		s += bonusWordStart
+	case unicode.IsUpper(cand[j]) && !unicode.IsUpper(cand[j-1]):
+		s += bonusWordStart
+	}
+
+	return s
+}
+
+func isSeparator(r rune) bool {
+	return strings.ContainsRune(" _-./:\\", r)
+}
@@ -0,0 +1,109 @@
+package fuzzy_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp/fuzzy"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		candidate string
+		want      []fuzzy.Range
+		ok        bool
+	}{
+		{
+			pattern:   "",
+			candidate: "foo",
+			want:      nil,
+			ok:        true,
+		},
+		{
+			pattern:   "foo",
+			candidate: "foobar",
+			want:      []fuzzy.Range{{Start: 0, End: 3}},
+			ok:        true,
+		},
+		{
+			pattern:   "fb",
+			candidate: "fooBar",
+			want:      []fuzzy.Range{{Start: 0, End: 1}, {Start: 3, End: 4}},
+			ok:        true,
+		},
+		{
+			pattern:   "bar",
+			candidate: "xbar_bar",
+			want:      []fuzzy.Range{{Start: 5, End: 8}},
+			ok:        true,
+		},
+		{
+			pattern:   "FB",
+			candidate: "foo_bar",
+			want:      []fuzzy.Range{{Start: 0, End: 1}, {Start: 4, End: 5}},
+			ok:        true,
+		},
+		{
+			pattern:   "あい",
+			candidate: "xあいう",
+			want:      []fuzzy.Range{{Start: 1, End: 7}},
+			ok:        true,
+		},
+		{
+			pattern:   "ba",
+			candidate: "abc",
+			ok:        false,
+		},
+		{
+			pattern:   "foobar",
+			candidate: "foo",
+			ok:        false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got, ok := fuzzy.Match(tt.pattern, tt.candidate)
+			if ok != tt.ok {
+				t.Fatalf("ok mismatch: want %v but got %v", tt.ok, ok)
+			}
+			if diff := cmp.Diff(tt.want, got.Ranges); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatch_Score(t *testing.T) {
+	// each candidate should be scored higher than the next one
+	cases := []struct {
+		pattern    string
+		candidates []string
+	}{
+		{
+			pattern:    "fb",
+			candidates: []string{"foo_bar", "fooBar", "FooBar", "fob", "xfxb"},
+		},
+		{
+			pattern:    "get",
+			candidates: []string{"getName", "GetName", "forget", "gxexxt"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			prev := -1
+			for i, c := range tt.candidates {
+				got, ok := fuzzy.Match(tt.pattern, c)
+				if !ok {
+					t.Fatalf("%q should match %q", tt.pattern, c)
+				}
+				if i > 0 && got.Score >= prev {
+					t.Fatalf("%q should be scored lower than %q: %d >= %d", c, tt.candidates[i-1], got.Score, prev)
+				}
+				prev = got.Score
+			}
+		})
+	}
+}
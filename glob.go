@@ -0,0 +1,79 @@
+package lsp
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// globRegexp compiles the glob pattern of the protocol into a regular expression matching the whole path.
+//
+//   - '*' matches zero or more characters in a path segment
+//   - '?' matches one character in a path segment
+//   - '**' matches any number of path segments, including none
+//   - '{}' groups the sub patterns separated by ','
+//   - '[]' declares a range of characters, '[!...]' negates it
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**") {
+				i++
+				if strings.HasPrefix(pattern[i+1:], "/") {
+					i++
+					b.WriteString("(?:.*/)?")
+					continue
+				}
+				b.WriteString(".*")
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '{':
+			depth++
+			b.WriteString("(?:")
+		case '}':
+			if depth == 0 {
+				b.WriteString(regexp.QuoteMeta("}"))
+				continue
+			}
+			depth--
+			b.WriteString(")")
+		case ',':
+			if depth == 0 {
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteString("|")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated character range")
+			}
+			class := pattern[i+1 : i+1+end]
+			i += end + 1
+
+			b.WriteString("[")
+			if strings.HasPrefix(class, "!") {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, "[", `\[`, "^", `\^`).Replace(class))
+			b.WriteString("]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	if depth != 0 {
+		return nil, errors.New("unterminated group")
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
@@ -3,6 +3,11 @@ package lsp
 import (
 	"encoding/json"
 	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/tennashi/lsp/snippet"
 )
 
 type IntOrString struct {
@@ -65,6 +70,14 @@ type Position struct {
 	Character int `json:"character"`
 }
 
+type PositionEncoding string
+
+const (
+	PositionEncodingUTF8  PositionEncoding = "utf-8"
+	PositionEncodingUTF16 PositionEncoding = "utf-16"
+	PositionEncodingUTF32 PositionEncoding = "utf-32"
+)
+
 type Range struct {
 	Start Position `json:"start"`
 	End   Position `json:"end"`
@@ -82,6 +95,88 @@ type LocationLink struct {
 	TargetSelectionRange Range       `json:"targetSelectionRange"`
 }
 
+// DefinitionResult is the result of the definition-like requests.
+// Either Locations or LocationLinks should be set.
+type DefinitionResult struct {
+	Locations     []Location
+	LocationLinks []LocationLink
+}
+
+func (v *DefinitionResult) MarshalJSON() ([]byte, error) {
+	if v.LocationLinks != nil {
+		if v.Locations != nil {
+			return nil, errors.New("both locations and location links are set")
+		}
+		return json.Marshal(v.LocationLinks)
+	}
+
+	if v.Locations == nil {
+		return json.Marshal([]Location{})
+	}
+
+	return json.Marshal(v.Locations)
+}
+
+func (v *DefinitionResult) UnmarshalJSON(d []byte) error {
+	if string(d) == "null" {
+		v.Locations = nil
+		v.LocationLinks = nil
+		return nil
+	}
+
+	loc := Location{}
+	if err := json.Unmarshal(d, &loc); err == nil {
+		v.Locations = []Location{loc}
+		v.LocationLinks = nil
+		return nil
+	}
+
+	tmp := []struct {
+		TargetURI *DocumentURI `json:"targetUri"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	if len(tmp) > 0 && tmp[0].TargetURI != nil {
+		links := []LocationLink{}
+		if err := json.Unmarshal(d, &links); err != nil {
+			return err
+		}
+		v.Locations = nil
+		v.LocationLinks = links
+		return nil
+	}
+
+	locs := []Location{}
+	if err := json.Unmarshal(d, &locs); err != nil {
+		return err
+	}
+	v.Locations = locs
+	v.LocationLinks = nil
+
+	return nil
+}
+
+// WithLinkSupport returns v as is if linkSupport is true.
+// Otherwise LocationLinks are converted to Locations pointing at their TargetSelectionRange.
+func (v DefinitionResult) WithLinkSupport(linkSupport bool) DefinitionResult {
+	if linkSupport || v.LocationLinks == nil {
+		return v
+	}
+
+	locs := make([]Location, 0, len(v.Locations)+len(v.LocationLinks))
+	locs = append(locs, v.Locations...)
+	for _, l := range v.LocationLinks {
+		locs = append(locs, Location{
+			URI:   l.TargetURI,
+			Range: l.TargetSelectionRange,
+		})
+	}
+
+	return DefinitionResult{Locations: locs}
+}
+
 type Diagnostic struct {
 	Range              Range                          `json:"range"`
 	Severity           DiagnosticSeverity             `json:"severity,omitempty"`
@@ -161,7 +256,7 @@ func (v *CreateFile) MarshalJSON() ([]byte, error) {
 func (v *CreateFile) UnmarshalJSON(d []byte) error {
 	tmp := struct {
 		Kind    string             `json:"kind"`
-		URI     DocumentURI        `json:"uri"`
+		URI     *DocumentURI       `json:"uri"`
 		Options *CreateFileOptions `json:"options,omitempty"`
 	}{}
 
@@ -174,8 +269,12 @@ func (v *CreateFile) UnmarshalJSON(d []byte) error {
 		return errors.New("invalid kind")
 	}
 
+	if tmp.URI == nil {
+		return errors.New("missing uri")
+	}
+
 	v.Kind = tmp.Kind
-	v.URI = tmp.URI
+	v.URI = *tmp.URI
 	v.Options = tmp.Options
 
 	return nil
@@ -212,8 +311,8 @@ func (v *RenameFile) MarshalJSON() ([]byte, error) {
 func (v *RenameFile) UnmarshalJSON(d []byte) error {
 	tmp := struct {
 		Kind    string             `json:"kind"`
-		OldURI  DocumentURI        `json:"oldUri"`
-		NewURI  DocumentURI        `json:"newUri"`
+		OldURI  *DocumentURI       `json:"oldUri"`
+		NewURI  *DocumentURI       `json:"newUri"`
 		Options *RenameFileOptions `json:"options,omitempty"`
 	}{}
 
@@ -226,9 +325,13 @@ func (v *RenameFile) UnmarshalJSON(d []byte) error {
 		return errors.New("invalid kind")
 	}
 
+	if tmp.OldURI == nil || tmp.NewURI == nil {
+		return errors.New("missing oldUri or newUri")
+	}
+
 	v.Kind = "rename"
-	v.OldURI = tmp.OldURI
-	v.NewURI = tmp.NewURI
+	v.OldURI = *tmp.OldURI
+	v.NewURI = *tmp.NewURI
 	v.Options = tmp.Options
 
 	return nil
@@ -262,7 +365,7 @@ func (v *DeleteFile) MarshalJSON() ([]byte, error) {
 func (v *DeleteFile) UnmarshalJSON(d []byte) error {
 	tmp := struct {
 		Kind    string             `json:"kind"`
-		URI     DocumentURI        `json:"uri"`
+		URI     *DocumentURI       `json:"uri"`
 		Options *DeleteFileOptions `json:"options,omitempty"`
 	}{}
 
@@ -275,8 +378,12 @@ func (v *DeleteFile) UnmarshalJSON(d []byte) error {
 		return errors.New("invalid kind")
 	}
 
+	if tmp.URI == nil {
+		return errors.New("missing uri")
+	}
+
 	v.Kind = "delete"
-	v.URI = tmp.URI
+	v.URI = *tmp.URI
 	v.Options = tmp.Options
 
 	return nil
@@ -284,137 +391,77 @@ func (v *DeleteFile) UnmarshalJSON(d []byte) error {
 
 type WorkspaceEdit struct {
 	Changes         map[DocumentURI][]TextEdit `json:"changes,omitempty"`
-	DocumentChanges *DocumentChanges           `json:"documentChanges,omitempty"`
-}
-
-type DocumentChanges struct {
-	TextDocumentEdits []TextDocumentEdit
-	CreateFiles       []CreateFile
-	RenameFiles       []RenameFile
-	DeleteFiles       []DeleteFile
-}
-
-type documentChange struct {
-	TextDocument  VersionedTextDocumentIdentifier
-	Edits         []TextEdit
-	Kind          string
-	URI           DocumentURI
-	OldURI        DocumentURI
-	NewURI        DocumentURI
-	CreateOptions *CreateFileOptions
-	RenameOptions *RenameFileOptions
-	DeleteOptions *DeleteFileOptions
-}
-
-func (v *documentChange) MarshalJSON() ([]byte, error) {
-	switch v.Kind {
-	case "create":
-		cf := CreateFile{
-			Kind:    v.Kind,
-			URI:     v.URI,
-			Options: v.CreateOptions,
-		}
-		return json.Marshal(cf)
-	case "rename":
-		rf := RenameFile{
-			Kind:    v.Kind,
-			OldURI:  v.OldURI,
-			NewURI:  v.NewURI,
-			Options: v.RenameOptions,
-		}
-		return json.Marshal(rf)
-	case "delete":
-		df := DeleteFile{
-			Kind:    v.Kind,
-			URI:     v.URI,
-			Options: v.DeleteOptions,
-		}
-		return json.Marshal(df)
+	DocumentChanges DocumentChanges            `json:"documentChanges,omitempty"`
+}
+
+// DocumentChanges keeps the operations in the order they should be applied.
+type DocumentChanges []DocumentChange
+
+// DocumentChange is one of TextDocumentEdit, CreateFile, RenameFile and DeleteFile.
+// Exactly one of the fields should be set.
+type DocumentChange struct {
+	TextDocumentEdit *TextDocumentEdit
+	CreateFile       *CreateFile
+	RenameFile       *RenameFile
+	DeleteFile       *DeleteFile
+}
+
+func (v *DocumentChange) MarshalJSON() ([]byte, error) {
+	switch {
+	case v.TextDocumentEdit != nil && v.CreateFile == nil && v.RenameFile == nil && v.DeleteFile == nil:
+		return json.Marshal(v.TextDocumentEdit)
+	case v.TextDocumentEdit == nil && v.CreateFile != nil && v.RenameFile == nil && v.DeleteFile == nil:
+		return json.Marshal(v.CreateFile)
+	case v.TextDocumentEdit == nil && v.CreateFile == nil && v.RenameFile != nil && v.DeleteFile == nil:
+		return json.Marshal(v.RenameFile)
+	case v.TextDocumentEdit == nil && v.CreateFile == nil && v.RenameFile == nil && v.DeleteFile != nil:
+		return json.Marshal(v.DeleteFile)
 	default:
-		tde := TextDocumentEdit{
-			TextDocument: v.TextDocument,
-			Edits:        v.Edits,
-		}
-		return json.Marshal(tde)
+		return nil, errors.New("exactly one document change should be set")
 	}
 }
 
-func (v *DocumentChanges) MarshalJSON() ([]byte, error) {
-	d := make([]documentChange, 0, len(v.TextDocumentEdits)+len(v.CreateFiles)+len(v.RenameFiles)+len(v.DeleteFiles))
-	for _, e := range v.TextDocumentEdits {
-		c := documentChange{
-			TextDocument: e.TextDocument,
-			Edits:        e.Edits,
-		}
-		d = append(d, c)
-	}
-
-	for _, e := range v.CreateFiles {
-		c := documentChange{
-			Kind:          e.Kind,
-			URI:           e.URI,
-			CreateOptions: e.Options,
-		}
-		d = append(d, c)
-	}
-
-	for _, e := range v.RenameFiles {
-		c := documentChange{
-			Kind:          e.Kind,
-			OldURI:        e.OldURI,
-			NewURI:        e.NewURI,
-			RenameOptions: e.Options,
-		}
-		d = append(d, c)
-	}
-
-	for _, e := range v.DeleteFiles {
-		c := documentChange{
-			Kind:          e.Kind,
-			URI:           e.URI,
-			DeleteOptions: e.Options,
-		}
-		d = append(d, c)
-	}
-
-	return json.Marshal(d)
-}
+func (v *DocumentChange) UnmarshalJSON(d []byte) error {
+	tmp := struct {
+		Kind         string           `json:"kind"`
+		TextDocument *json.RawMessage `json:"textDocument"`
+		Edits        *json.RawMessage `json:"edits"`
+	}{}
 
-func (v *DocumentChanges) UnmarshalJSON(b []byte) error {
-	d := []json.RawMessage{}
-	err := json.Unmarshal(b, &d)
-	if err != nil {
+	if err := json.Unmarshal(d, &tmp); err != nil {
 		return err
 	}
 
-	for _, e := range d {
+	switch tmp.Kind {
+	case "create":
 		cf := CreateFile{}
-		err = json.Unmarshal(e, &cf)
-		if err == nil {
-			v.CreateFiles = append(v.CreateFiles, cf)
-			continue
+		if err := json.Unmarshal(d, &cf); err != nil {
+			return err
 		}
-
+		*v = DocumentChange{CreateFile: &cf}
+	case "rename":
 		rf := RenameFile{}
-		err = json.Unmarshal(e, &rf)
-		if err == nil {
-			v.RenameFiles = append(v.RenameFiles, rf)
-			continue
+		if err := json.Unmarshal(d, &rf); err != nil {
+			return err
 		}
-
+		*v = DocumentChange{RenameFile: &rf}
+	case "delete":
 		df := DeleteFile{}
-		err = json.Unmarshal(e, &df)
-		if err == nil {
-			v.DeleteFiles = append(v.DeleteFiles, df)
-			continue
+		if err := json.Unmarshal(d, &df); err != nil {
+			return err
+		}
+		*v = DocumentChange{DeleteFile: &df}
+	case "":
+		if tmp.TextDocument == nil || tmp.Edits == nil {
+			return errors.New("unknown document change")
 		}
-
 		tde := TextDocumentEdit{}
-		err := json.Unmarshal(e, &tde)
-		if err == nil {
-			v.TextDocumentEdits = append(v.TextDocumentEdits, tde)
-			continue
+		if err := json.Unmarshal(d, &tde); err != nil {
+			return err
 		}
+		*v = DocumentChange{TextDocumentEdit: &tde}
+	default:
+		return errors.New("invalid kind")
 	}
 
 	return nil
@@ -473,6 +520,222 @@ const (
 	MarkupKindMarkdown  MarkupKind = "markdown"
 )
 
+// MarkedString is a markdown string if Language is empty, a code block in Language otherwise.
+type MarkedString struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+func (v *MarkedString) MarshalJSON() ([]byte, error) {
+	if v.Language == "" {
+		return json.Marshal(v.Value)
+	}
+
+	type markedString MarkedString
+	return json.Marshal((*markedString)(v))
+}
+
+func (v *MarkedString) UnmarshalJSON(d []byte) error {
+	s := ""
+	if err := json.Unmarshal(d, &s); err == nil {
+		v.Language = ""
+		v.Value = s
+		return nil
+	}
+
+	type markedString MarkedString
+	tmp := markedString{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+	*v = MarkedString(tmp)
+
+	return nil
+}
+
+// MarkupUnion is string | MarkupContent | MarkedString | []MarkedString.
+// Exactly one of the fields must be set.
+// A MarkedString with an empty Language is encoded as a string, so it is decoded into String.
+type MarkupUnion struct {
+	String        *string
+	MarkupContent *MarkupContent
+	MarkedString  *MarkedString
+	MarkedStrings []MarkedString
+}
+
+func (v *MarkupUnion) MarshalJSON() ([]byte, error) {
+	n := 0
+	var res interface{}
+	if v.String != nil {
+		n++
+		res = v.String
+	}
+	if v.MarkupContent != nil {
+		n++
+		res = v.MarkupContent
+	}
+	if v.MarkedString != nil {
+		n++
+		res = v.MarkedString
+	}
+	if v.MarkedStrings != nil {
+		n++
+		res = v.MarkedStrings
+	}
+
+	if n != 1 {
+		return nil, errors.New("exactly one content must be set")
+	}
+
+	return json.Marshal(res)
+}
+
+func (v *MarkupUnion) UnmarshalJSON(d []byte) error {
+	*v = MarkupUnion{}
+
+	s := ""
+	if err := json.Unmarshal(d, &s); err == nil {
+		v.String = &s
+		return nil
+	}
+
+	ms := []MarkedString{}
+	if err := json.Unmarshal(d, &ms); err == nil {
+		v.MarkedStrings = ms
+		return nil
+	}
+
+	tmp := struct {
+		Kind     *MarkupKind `json:"kind"`
+		Language *string     `json:"language"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	switch {
+	case tmp.Kind != nil:
+		mc := MarkupContent{}
+		if err := json.Unmarshal(d, &mc); err != nil {
+			return err
+		}
+		v.MarkupContent = &mc
+	case tmp.Language != nil:
+		m := MarkedString{}
+		if err := json.Unmarshal(d, &m); err != nil {
+			return err
+		}
+		v.MarkedString = &m
+	default:
+		return errors.New("unknown content")
+	}
+
+	return nil
+}
+
+// WithFormats returns v converted for the client which supports formats.
+// If formats is not empty and does not contain markdown, the markdown is converted to plaintext MarkupContent.
+// A String is returned as is since it is plaintext in the documentations.
+func (v MarkupUnion) WithFormats(formats []MarkupKind) MarkupUnion {
+	if len(formats) == 0 || v.String != nil {
+		return v
+	}
+	for _, f := range formats {
+		if f == MarkupKindMarkdown {
+			return v
+		}
+	}
+
+	var value string
+	switch {
+	case v.MarkupContent != nil:
+		if v.MarkupContent.Kind != MarkupKindMarkdown {
+			return v
+		}
+		value = markdownToPlainText(v.MarkupContent.Value)
+	case v.MarkedString != nil:
+		value = v.MarkedString.plainText()
+	case v.MarkedStrings != nil:
+		values := make([]string, 0, len(v.MarkedStrings))
+		for _, m := range v.MarkedStrings {
+			values = append(values, m.plainText())
+		}
+		value = strings.Join(values, "\n\n")
+	default:
+		return v
+	}
+
+	return MarkupUnion{
+		MarkupContent: &MarkupContent{
+			Kind:  MarkupKindPlainText,
+			Value: value,
+		},
+	}
+}
+
+func (v MarkedString) plainText() string {
+	if v.Language != "" {
+		return v.Value
+	}
+	return markdownToPlainText(v.Value)
+}
+
+// markdownToPlainText strips the markdown syntax which gets in the way of reading the text as is:
+// code fences, heading markers, backslash escapes and inline code backticks.
+func markdownToPlainText(s string) string {
+	lines := strings.Split(s, "\n")
+	res := make([]string, 0, len(lines))
+
+	fence := ""
+	for _, l := range lines {
+		trimmed := strings.TrimLeft(l, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])) == "" {
+				fence = ""
+				continue
+			}
+			res = append(res, l)
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, trimmed[:1]))
+			fence = trimmed[:n]
+			continue
+		}
+
+		h := strings.TrimLeft(trimmed, "#")
+		if n := len(trimmed) - len(h); n >= 1 && n <= 6 && (h == "" || h[0] == ' ') {
+			h = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(h), "#"))
+			res = append(res, unescapeMarkdown(h))
+			continue
+		}
+
+		res = append(res, unescapeMarkdown(l))
+	}
+
+	return strings.Join(res, "\n")
+}
+
+func unescapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(markdownPunctuation, s[i+1]) >= 0:
+			i++
+			b.WriteByte(s[i])
+		case c == '`':
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+const markdownPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
 // ### Work Done Progress
 
 type WorkDoneProgressBegin struct {
@@ -649,8 +912,8 @@ type CompletionItem struct {
 	Kind                CompletionItemKind  `json:"kind,omitempty"`
 	Tags                []CompletionItemTag `json:"tags,omitempty"`
 	Detail              string              `json:"detail,omitempty"`
-	Documentation       interface{}         `json:"documentation,omitempty"` // string | MarkupContent
-	Deprecated          bool                `json:"deprecated,omitempty"`    // deprecated
+	Documentation       *MarkupUnion        `json:"documentation,omitempty"`
+	Deprecated          bool                `json:"deprecated,omitempty"` // deprecated
 	Preselect           bool                `json:"preselect,omitempty"`
 	SortText            string              `json:"sortText,omitempty"`
 	FilterText          string              `json:"filterText,omitempty"`
@@ -663,6 +926,56 @@ type CompletionItem struct {
 	Data                interface{}         `json:"data,omitempty"`
 }
 
+func (v CompletionItem) withDocumentationFormat(formats []MarkupKind) CompletionItem {
+	if v.Documentation != nil {
+		doc := v.Documentation.WithFormats(formats)
+		v.Documentation = &doc
+	}
+	return v
+}
+
+// deferred reports whether v leaves any of props, the properties the client can resolve lazily, to be resolved.
+func (v CompletionItem) deferred(props []string) bool {
+	for _, p := range props {
+		switch {
+		case p == "detail" && v.Detail == "",
+			p == "documentation" && v.Documentation == nil,
+			p == "additionalTextEdits" && v.AdditionalTextEdits == nil,
+			p == "textEdit" && v.TextEdit == nil,
+			p == "command" && v.Command == nil:
+			return true
+		}
+	}
+	return false
+}
+
+// withSnippetSupport returns v as is if snippetSupport is true.
+// Otherwise the snippets are converted to plain text, and the invalid ones are replaced with the label
+// not to insert the raw snippet syntax.
+func (v CompletionItem) withSnippetSupport(snippetSupport bool) CompletionItem {
+	if snippetSupport || v.InsertTextFormat != InsertTextFormatSnippet {
+		return v
+	}
+
+	plainText := func(s string) string {
+		text, err := snippet.PlainText(s)
+		if err != nil {
+			return v.Label
+		}
+		return text
+	}
+
+	if v.InsertText != "" {
+		v.InsertText = plainText(v.InsertText)
+	}
+	if v.TextEdit != nil {
+		v.TextEdit = &TextEdit{Range: v.TextEdit.Range, NewText: plainText(v.TextEdit.NewText)}
+	}
+	v.InsertTextFormat = InsertTextFormatPlainText
+
+	return v
+}
+
 type CompletionItemKind int
 
 const (
@@ -695,8 +1008,18 @@ const (
 )
 
 type Hover struct {
-	Contents MarkupContent `json:"contents"`
-	Range    Range         `json:"range"`
+	Contents MarkupUnion `json:"contents"`
+	Range    *Range      `json:"range,omitempty"`
+}
+
+// withContentFormat returns v whose contents are converted for the client which supports formats.
+// Unlike the documentations, a String in the contents is a markdown MarkedString.
+func (v Hover) withContentFormat(formats []MarkupKind) Hover {
+	if v.Contents.String != nil {
+		v.Contents = MarkupUnion{MarkedString: &MarkedString{Value: *v.Contents.String}}
+	}
+	v.Contents = v.Contents.WithFormats(formats)
+	return v
 }
 
 type SignatureHelpTriggerKind int
@@ -723,13 +1046,34 @@ type SignatureHelp struct {
 
 type SignatureInformation struct {
 	Label         string                 `json:"label"`
-	Documentation interface{}            `json:"documentation,omitempty"` // string | MarkupContent
+	Documentation *MarkupUnion           `json:"documentation,omitempty"`
 	Parameters    []ParameterInformation `json:"parameters,omitempty"`
 }
 
+func (v SignatureInformation) withDocumentationFormat(formats []MarkupKind) SignatureInformation {
+	if v.Documentation != nil {
+		doc := v.Documentation.WithFormats(formats)
+		v.Documentation = &doc
+	}
+
+	if v.Parameters != nil {
+		params := make([]ParameterInformation, 0, len(v.Parameters))
+		for _, p := range v.Parameters {
+			if p.Documentation != nil {
+				doc := p.Documentation.WithFormats(formats)
+				p.Documentation = &doc
+			}
+			params = append(params, p)
+		}
+		v.Parameters = params
+	}
+
+	return v
+}
+
 type ParameterInformation struct {
-	Label         interface{} `json:"label"`                   // string | [number, number]
-	Documentation interface{} `json:"documentation,omitempty"` // string | MarkupContent
+	Label         interface{}  `json:"label"` // string | [number, number]
+	Documentation *MarkupUnion `json:"documentation,omitempty"`
 }
 
 type ReferenceContext struct {
@@ -800,6 +1144,201 @@ type SymbolInformation struct {
 	ContainerName string     `json:"containerName,omitempty"`
 }
 
+// WorkspaceSymbolLocation is the location of WorkspaceSymbol.
+// Range may be left out to be resolved by workspaceSymbol/resolve.
+type WorkspaceSymbolLocation struct {
+	URI   DocumentURI `json:"uri"`
+	Range *Range      `json:"range,omitempty"`
+}
+
+type WorkspaceSymbol struct {
+	Name          string                  `json:"name"`
+	Kind          SymbolKind              `json:"kind"`
+	Tags          []SymbolTag             `json:"tags,omitempty"`
+	ContainerName string                  `json:"containerName,omitempty"`
+	Location      WorkspaceSymbolLocation `json:"location"`
+	Data          interface{}             `json:"data,omitempty"`
+}
+
+// DocumentSymbolResult is the result of textDocument/documentSymbol.
+// Either DocumentSymbols or SymbolInformation should be set.
+type DocumentSymbolResult struct {
+	DocumentSymbols   []DocumentSymbol
+	SymbolInformation []SymbolInformation
+}
+
+// NewDocumentSymbolResult builds the result for the client from the symbol tree of the document uri.
+// The tree is flattened if the client does not support hierarchical document symbols,
+// and the kinds the client does not support are replaced with the fallback kinds.
+func NewDocumentSymbolResult(uri DocumentURI, symbols []DocumentSymbol, caps *DocumentSymbolClientCapabilities) DocumentSymbolResult {
+	var valueSet []SymbolKind
+	if caps != nil && caps.SymbolKind != nil {
+		valueSet = caps.SymbolKind.ValueSet
+	}
+	symbols = filterSymbolKinds(symbols, valueSet)
+
+	if caps != nil && caps.HierarchicalDocumentSymbolSupport {
+		return DocumentSymbolResult{DocumentSymbols: symbols}
+	}
+
+	return DocumentSymbolResult{SymbolInformation: flattenDocumentSymbols(uri, symbols, "")}
+}
+
+func (v *DocumentSymbolResult) MarshalJSON() ([]byte, error) {
+	if v.SymbolInformation != nil {
+		if v.DocumentSymbols != nil {
+			return nil, errors.New("both document symbols and symbol information are set")
+		}
+		return json.Marshal(v.SymbolInformation)
+	}
+
+	if v.DocumentSymbols == nil {
+		return json.Marshal([]DocumentSymbol{})
+	}
+
+	return json.Marshal(v.DocumentSymbols)
+}
+
+func (v *DocumentSymbolResult) UnmarshalJSON(d []byte) error {
+	tmp := []struct {
+		Location *json.RawMessage `json:"location"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	if len(tmp) > 0 && tmp[0].Location != nil {
+		infos := []SymbolInformation{}
+		if err := json.Unmarshal(d, &infos); err != nil {
+			return err
+		}
+		v.DocumentSymbols = nil
+		v.SymbolInformation = infos
+		return nil
+	}
+
+	syms := []DocumentSymbol{}
+	if err := json.Unmarshal(d, &syms); err != nil {
+		return err
+	}
+	v.DocumentSymbols = syms
+	v.SymbolInformation = nil
+
+	return nil
+}
+
+func flattenDocumentSymbols(uri DocumentURI, symbols []DocumentSymbol, container string) []SymbolInformation {
+	res := []SymbolInformation{}
+	for _, sym := range symbols {
+		res = append(res, SymbolInformation{
+			Name:       sym.Name,
+			Kind:       sym.Kind,
+			Deprecated: sym.Deprecated,
+			Location: Location{
+				URI:   uri,
+				Range: sym.Range,
+			},
+			ContainerName: container,
+		})
+		res = append(res, flattenDocumentSymbols(uri, sym.Children, sym.Name)...)
+	}
+
+	return res
+}
+
+// defaultSymbolKinds is the kinds supported by the clients which do not send the value set.
+var defaultSymbolKinds = []SymbolKind{
+	SymbolKindFile,
+	SymbolKindModule,
+	SymbolKindNamespace,
+	SymbolKindPackage,
+	SymbolKindClass,
+	SymbolKindMethod,
+	SymbolKindProperty,
+	SymbolKindField,
+	SymbolKindConstructor,
+	SymbolKindEnum,
+	SymbolKindInterface,
+	SymbolKindFunction,
+	SymbolKindVariable,
+	SymbolKindConstant,
+	SymbolKindString,
+	SymbolKindNumber,
+	SymbolKindBoolean,
+	SymbolKindArray,
+}
+
+var symbolKindFallbacks = map[SymbolKind]SymbolKind{
+	SymbolKindNamespace:     SymbolKindModule,
+	SymbolKindPackage:       SymbolKindModule,
+	SymbolKindModule:        SymbolKindFile,
+	SymbolKindStruct:        SymbolKindClass,
+	SymbolKindObject:        SymbolKindClass,
+	SymbolKindInterface:     SymbolKindClass,
+	SymbolKindEnum:          SymbolKindClass,
+	SymbolKindClass:         SymbolKindVariable,
+	SymbolKindConstructor:   SymbolKindMethod,
+	SymbolKindOperator:      SymbolKindMethod,
+	SymbolKindMethod:        SymbolKindFunction,
+	SymbolKindFunction:      SymbolKindVariable,
+	SymbolKindKey:           SymbolKindProperty,
+	SymbolKindEvent:         SymbolKindProperty,
+	SymbolKindProperty:      SymbolKindField,
+	SymbolKindField:         SymbolKindVariable,
+	SymbolKindEnumMember:    SymbolKindConstant,
+	SymbolKindNull:          SymbolKindConstant,
+	SymbolKindString:        SymbolKindConstant,
+	SymbolKindNumber:        SymbolKindConstant,
+	SymbolKindBoolean:       SymbolKindConstant,
+	SymbolKindArray:         SymbolKindVariable,
+	SymbolKindConstant:      SymbolKindVariable,
+	SymbolKindTypeParameter: SymbolKindVariable,
+}
+
+func supportedSymbolKind(kind SymbolKind, valueSet []SymbolKind) SymbolKind {
+	if len(valueSet) == 0 {
+		valueSet = defaultSymbolKinds
+	}
+
+	supported := func(k SymbolKind) bool {
+		for _, v := range valueSet {
+			if v == k {
+				return true
+			}
+		}
+		return false
+	}
+
+	for k := kind; ; {
+		if supported(k) {
+			return k
+		}
+
+		next, ok := symbolKindFallbacks[k]
+		if !ok {
+			break
+		}
+		k = next
+	}
+
+	return valueSet[0]
+}
+
+func filterSymbolKinds(symbols []DocumentSymbol, valueSet []SymbolKind) []DocumentSymbol {
+	if symbols == nil {
+		return nil
+	}
+
+	res := make([]DocumentSymbol, 0, len(symbols))
+	for _, sym := range symbols {
+		sym.Kind = supportedSymbolKind(sym.Kind, valueSet)
+		sym.Children = filterSymbolKinds(sym.Children, valueSet)
+		res = append(res, sym)
+	}
+
+	return res
+}
+
 type CodeActionKind string
 
 const (
@@ -825,15 +1364,169 @@ type CodeAction struct {
 	IsPreferred bool           `json:"isPreferred,omitempty"`
 	Edit        *WorkspaceEdit `json:"edit,omitempty"`
 	Command     *Command       `json:"command,omitempty"`
+	Data        interface{}    `json:"data,omitempty"`
 }
 
-type CodeLens struct {
-	Range   Range       `json:"range"`
-	Command *Command    `json:"command,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+// deferred reports whether v leaves the edit to be resolved.
+// The action only with the command has nothing to resolve.
+func (v CodeAction) deferred() bool {
+	return v.Edit == nil && v.Command == nil
 }
 
-type DocumentLink struct {
+// CodeActionApplyEditCommand is the command which the code actions with the edit are converted to
+// for the clients without the code action literal support.
+// The server applies the edit through workspace/applyEdit when the command is executed.
+const CodeActionApplyEditCommand = "lsp.codeAction.applyEdit"
+
+// CodeActionOrCommand is either CodeAction or Command.
+type CodeActionOrCommand struct {
+	CodeAction *CodeAction
+	Command    *Command
+}
+
+func (v *CodeActionOrCommand) MarshalJSON() ([]byte, error) {
+	switch {
+	case v.CodeAction != nil && v.Command == nil:
+		return json.Marshal(v.CodeAction)
+	case v.CodeAction == nil && v.Command != nil:
+		return json.Marshal(v.Command)
+	default:
+		return nil, errors.New("exactly one of code action and command should be set")
+	}
+}
+
+func (v *CodeActionOrCommand) UnmarshalJSON(d []byte) error {
+	tmp := struct {
+		Command json.RawMessage `json:"command"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	cmd := ""
+	if err := json.Unmarshal(tmp.Command, &cmd); err == nil {
+		c := Command{}
+		if err := json.Unmarshal(d, &c); err != nil {
+			return err
+		}
+		*v = CodeActionOrCommand{Command: &c}
+		return nil
+	}
+
+	a := CodeAction{}
+	if err := json.Unmarshal(d, &a); err != nil {
+		return err
+	}
+	*v = CodeActionOrCommand{CodeAction: &a}
+
+	return nil
+}
+
+type CodeActionResult []CodeActionOrCommand
+
+// Filter drops the code actions whose kinds are not requested by only or not supported by the client,
+// and adapts the rest to the client capabilities.
+// The code actions are converted to commands if the client does not support the code action literals,
+// those with the edit become CodeActionApplyEditCommand.
+func (v CodeActionResult) Filter(only []CodeActionKind, caps *CodeActionClientCapabilities) CodeActionResult {
+	if caps == nil {
+		caps = &CodeActionClientCapabilities{}
+	}
+
+	res := CodeActionResult{}
+	for _, e := range v.filterKinds(only, caps) {
+		if e.CodeAction == nil {
+			res = append(res, e)
+			continue
+		}
+
+		a := *e.CodeAction
+		if caps.CodeActionLiteralSupport != nil {
+			if !caps.IsPreferredSupport {
+				a.IsPreferred = false
+			}
+			res = append(res, CodeActionOrCommand{CodeAction: &a})
+			continue
+		}
+
+		switch {
+		case a.Edit != nil:
+			args := []interface{}{a.Edit}
+			if a.Command != nil {
+				args = append(args, a.Command)
+			}
+			res = append(res, CodeActionOrCommand{
+				Command: &Command{
+					Title:     a.Title,
+					Command:   CodeActionApplyEditCommand,
+					Arguments: args,
+				},
+			})
+		case a.Command != nil:
+			res = append(res, CodeActionOrCommand{Command: a.Command})
+		}
+	}
+
+	return res
+}
+
+// filterKinds drops the code actions whose kinds are not requested by only or not supported by the client
+// without adapting the rest.
+func (v CodeActionResult) filterKinds(only []CodeActionKind, caps *CodeActionClientCapabilities) CodeActionResult {
+	if caps == nil {
+		caps = &CodeActionClientCapabilities{}
+	}
+
+	var valueSet []CodeActionKind
+	if caps.CodeActionLiteralSupport != nil && caps.CodeActionLiteralSupport.CodeActionKind != nil {
+		valueSet = caps.CodeActionLiteralSupport.CodeActionKind.ValueSet
+	}
+
+	res := CodeActionResult{}
+	for _, e := range v {
+		if e.CodeAction == nil {
+			if len(only) == 0 {
+				res = append(res, e)
+			}
+			continue
+		}
+
+		kind := e.CodeAction.Kind
+		if len(only) != 0 && !matchCodeActionKind(kind, only) {
+			continue
+		}
+		if caps.CodeActionLiteralSupport != nil && kind != CodeActionKindEmpty && len(valueSet) != 0 && !matchCodeActionKind(kind, valueSet) {
+			continue
+		}
+		res = append(res, e)
+	}
+
+	return res
+}
+
+// matchCodeActionKind reports whether kind is one of bases or their sub kinds.
+func matchCodeActionKind(kind CodeActionKind, bases []CodeActionKind) bool {
+	for _, b := range bases {
+		if kind == b || (b != CodeActionKindEmpty && strings.HasPrefix(string(kind), string(b)+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+type ApplyWorkspaceEditResponse struct {
+	Applied       bool   `json:"applied"`
+	FailureReason string `json:"failureReason,omitempty"`
+	FailedChange  *int   `json:"failedChange,omitempty"`
+}
+
+type CodeLens struct {
+	Range   Range       `json:"range"`
+	Command *Command    `json:"command,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type DocumentLink struct {
 	Range   Range       `json:"range"`
 	Target  DocumentURI `json:"target,omitempty"`
 	Tooltip string      `json:"tooltip,omitempty"`
@@ -887,3 +1580,874 @@ type SelectionRange struct {
 	Range  Range           `json:"range"`
 	Parent *SelectionRange `json:"parent,omitempty"`
 }
+
+type SemanticTokenType string
+
+const (
+	SemanticTokenTypeNamespace     SemanticTokenType = "namespace"
+	SemanticTokenTypeType          SemanticTokenType = "type"
+	SemanticTokenTypeClass         SemanticTokenType = "class"
+	SemanticTokenTypeEnum          SemanticTokenType = "enum"
+	SemanticTokenTypeInterface     SemanticTokenType = "interface"
+	SemanticTokenTypeStruct        SemanticTokenType = "struct"
+	SemanticTokenTypeTypeParameter SemanticTokenType = "typeParameter"
+	SemanticTokenTypeParameter     SemanticTokenType = "parameter"
+	SemanticTokenTypeVariable      SemanticTokenType = "variable"
+	SemanticTokenTypeProperty      SemanticTokenType = "property"
+	SemanticTokenTypeEnumMember    SemanticTokenType = "enumMember"
+	SemanticTokenTypeEvent         SemanticTokenType = "event"
+	SemanticTokenTypeFunction      SemanticTokenType = "function"
+	SemanticTokenTypeMethod        SemanticTokenType = "method"
+	SemanticTokenTypeMacro         SemanticTokenType = "macro"
+	SemanticTokenTypeKeyword       SemanticTokenType = "keyword"
+	SemanticTokenTypeModifier      SemanticTokenType = "modifier"
+	SemanticTokenTypeComment       SemanticTokenType = "comment"
+	SemanticTokenTypeString        SemanticTokenType = "string"
+	SemanticTokenTypeNumber        SemanticTokenType = "number"
+	SemanticTokenTypeRegexp        SemanticTokenType = "regexp"
+	SemanticTokenTypeOperator      SemanticTokenType = "operator"
+	SemanticTokenTypeDecorator     SemanticTokenType = "decorator"
+)
+
+type SemanticTokenModifier string
+
+const (
+	SemanticTokenModifierDeclaration    SemanticTokenModifier = "declaration"
+	SemanticTokenModifierDefinition     SemanticTokenModifier = "definition"
+	SemanticTokenModifierReadonly       SemanticTokenModifier = "readonly"
+	SemanticTokenModifierStatic         SemanticTokenModifier = "static"
+	SemanticTokenModifierDeprecated     SemanticTokenModifier = "deprecated"
+	SemanticTokenModifierAbstract       SemanticTokenModifier = "abstract"
+	SemanticTokenModifierAsync          SemanticTokenModifier = "async"
+	SemanticTokenModifierModification   SemanticTokenModifier = "modification"
+	SemanticTokenModifierDocumentation  SemanticTokenModifier = "documentation"
+	SemanticTokenModifierDefaultLibrary SemanticTokenModifier = "defaultLibrary"
+)
+
+type TokenFormat string
+
+const (
+	TokenFormatRelative TokenFormat = "relative"
+)
+
+type SemanticTokensLegend struct {
+	TokenTypes     []SemanticTokenType     `json:"tokenTypes"`
+	TokenModifiers []SemanticTokenModifier `json:"tokenModifiers"`
+}
+
+type SemanticTokens struct {
+	ResultID string `json:"resultId,omitempty"`
+	Data     []int  `json:"data"`
+}
+
+type SemanticTokensEdit struct {
+	Start       int   `json:"start"`
+	DeleteCount int   `json:"deleteCount"`
+	Data        []int `json:"data,omitempty"`
+}
+
+type SemanticTokensDelta struct {
+	ResultID string               `json:"resultId,omitempty"`
+	Edits    []SemanticTokensEdit `json:"edits"`
+}
+
+// SemanticTokensRange is true | {}.
+type SemanticTokensRange bool
+
+func (v *SemanticTokensRange) UnmarshalJSON(d []byte) error {
+	b := false
+	if err := json.Unmarshal(d, &b); err == nil {
+		*v = SemanticTokensRange(b)
+		return nil
+	}
+
+	tmp := struct{}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+	*v = true
+
+	return nil
+}
+
+// SemanticTokensFull is false | true | {"delta": true}.
+type SemanticTokensFull int
+
+const (
+	SemanticTokensFullUnsupported SemanticTokensFull = iota
+	SemanticTokensFullSupported
+	SemanticTokensFullDelta
+)
+
+func (v *SemanticTokensFull) MarshalJSON() ([]byte, error) {
+	switch *v {
+	case SemanticTokensFullUnsupported:
+		return json.Marshal(false)
+	case SemanticTokensFullSupported:
+		return json.Marshal(true)
+	default:
+		return json.Marshal(struct {
+			Delta bool `json:"delta"`
+		}{Delta: true})
+	}
+}
+
+func (v *SemanticTokensFull) UnmarshalJSON(d []byte) error {
+	b := false
+	if err := json.Unmarshal(d, &b); err == nil {
+		if b {
+			*v = SemanticTokensFullSupported
+		} else {
+			*v = SemanticTokensFullUnsupported
+		}
+		return nil
+	}
+
+	tmp := struct {
+		Delta bool `json:"delta"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	if tmp.Delta {
+		*v = SemanticTokensFullDelta
+	} else {
+		*v = SemanticTokensFullSupported
+	}
+
+	return nil
+}
+
+type SymbolTag int
+
+const (
+	SymbolTagUnknown SymbolTag = iota
+	SymbolTagDeprecated
+)
+
+type CallHierarchyItem struct {
+	Name           string      `json:"name"`
+	Kind           SymbolKind  `json:"kind"`
+	Tags           []SymbolTag `json:"tags,omitempty"`
+	Detail         string      `json:"detail,omitempty"`
+	URI            DocumentURI `json:"uri"`
+	Range          Range       `json:"range"`
+	SelectionRange Range       `json:"selectionRange"`
+	Data           interface{} `json:"data,omitempty"`
+}
+
+type CallHierarchyIncomingCall struct {
+	From       CallHierarchyItem `json:"from"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+type CallHierarchyOutgoingCall struct {
+	To         CallHierarchyItem `json:"to"`
+	FromRanges []Range           `json:"fromRanges"`
+}
+
+type TypeHierarchyItem struct {
+	Name           string      `json:"name"`
+	Kind           SymbolKind  `json:"kind"`
+	Tags           []SymbolTag `json:"tags,omitempty"`
+	Detail         string      `json:"detail,omitempty"`
+	URI            DocumentURI `json:"uri"`
+	Range          Range       `json:"range"`
+	SelectionRange Range       `json:"selectionRange"`
+	Data           interface{} `json:"data,omitempty"`
+}
+
+type InlayHintKind int
+
+const (
+	InlayHintKindUnknown InlayHintKind = iota
+	InlayHintKindType
+	InlayHintKindParameter
+)
+
+type InlayHintLabelPart struct {
+	Value    string       `json:"value"`
+	Tooltip  *MarkupUnion `json:"tooltip,omitempty"` // string | MarkupContent
+	Location *Location    `json:"location,omitempty"`
+	Command  *Command     `json:"command,omitempty"`
+}
+
+// InlayHintLabel is string | []InlayHintLabelPart.
+// It is encoded as the parts if Parts is not nil, Value otherwise.
+type InlayHintLabel struct {
+	Value string
+	Parts []InlayHintLabelPart
+}
+
+func (v *InlayHintLabel) MarshalJSON() ([]byte, error) {
+	if v.Parts != nil {
+		if v.Value != "" {
+			return nil, errors.New("both value and parts are set")
+		}
+		return json.Marshal(v.Parts)
+	}
+
+	return json.Marshal(v.Value)
+}
+
+func (v *InlayHintLabel) UnmarshalJSON(d []byte) error {
+	s := ""
+	if err := json.Unmarshal(d, &s); err == nil {
+		v.Value = s
+		v.Parts = nil
+		return nil
+	}
+
+	parts := []InlayHintLabelPart{}
+	if err := json.Unmarshal(d, &parts); err != nil {
+		return err
+	}
+	v.Value = ""
+	v.Parts = parts
+
+	return nil
+}
+
+type InlayHint struct {
+	Position     Position       `json:"position"`
+	Label        InlayHintLabel `json:"label"`
+	Kind         InlayHintKind  `json:"kind,omitempty"`
+	TextEdits    []TextEdit     `json:"textEdits,omitempty"`
+	Tooltip      *MarkupUnion   `json:"tooltip,omitempty"` // string | MarkupContent
+	PaddingLeft  bool           `json:"paddingLeft,omitempty"`
+	PaddingRight bool           `json:"paddingRight,omitempty"`
+	Data         interface{}    `json:"data,omitempty"`
+}
+
+type DocumentDiagnosticReportKind string
+
+const (
+	DocumentDiagnosticReportKindFull      DocumentDiagnosticReportKind = "full"
+	DocumentDiagnosticReportKindUnchanged DocumentDiagnosticReportKind = "unchanged"
+)
+
+type FullDocumentDiagnosticReport struct {
+	ResultID string
+	Items    []Diagnostic
+}
+
+type UnchangedDocumentDiagnosticReport struct {
+	ResultID string
+}
+
+// diagnosticReport is the JSON form of the full and the unchanged reports.
+type diagnosticReport struct {
+	Kind     DocumentDiagnosticReportKind `json:"kind"`
+	ResultID string                       `json:"resultId,omitempty"`
+	Items    *[]Diagnostic                `json:"items,omitempty"`
+}
+
+func newDiagnosticReport(full *FullDocumentDiagnosticReport, unchanged *UnchangedDocumentDiagnosticReport) (diagnosticReport, error) {
+	switch {
+	case full != nil && unchanged != nil:
+		return diagnosticReport{}, errors.New("both full and unchanged reports are set")
+	case full != nil:
+		items := full.Items
+		if items == nil {
+			items = []Diagnostic{}
+		}
+		return diagnosticReport{
+			Kind:     DocumentDiagnosticReportKindFull,
+			ResultID: full.ResultID,
+			Items:    &items,
+		}, nil
+	case unchanged != nil:
+		if unchanged.ResultID == "" {
+			return diagnosticReport{}, errors.New("missing result id")
+		}
+		return diagnosticReport{
+			Kind:     DocumentDiagnosticReportKindUnchanged,
+			ResultID: unchanged.ResultID,
+		}, nil
+	default:
+		return diagnosticReport{}, errors.New("no report is set")
+	}
+}
+
+func (r diagnosticReport) split() (*FullDocumentDiagnosticReport, *UnchangedDocumentDiagnosticReport, error) {
+	switch r.Kind {
+	case DocumentDiagnosticReportKindFull:
+		if r.Items == nil {
+			return nil, nil, errors.New("missing items")
+		}
+		return &FullDocumentDiagnosticReport{ResultID: r.ResultID, Items: *r.Items}, nil, nil
+	case DocumentDiagnosticReportKindUnchanged:
+		if r.ResultID == "" {
+			return nil, nil, errors.New("missing result id")
+		}
+		return nil, &UnchangedDocumentDiagnosticReport{ResultID: r.ResultID}, nil
+	default:
+		return nil, nil, errors.New("invalid kind")
+	}
+}
+
+// DocumentDiagnosticReport is the result of textDocument/diagnostic.
+// Exactly one of Full and Unchanged must be set.
+type DocumentDiagnosticReport struct {
+	Full             *FullDocumentDiagnosticReport
+	Unchanged        *UnchangedDocumentDiagnosticReport
+	RelatedDocuments map[DocumentURI]RelatedDocumentDiagnosticReport
+}
+
+func (v *DocumentDiagnosticReport) MarshalJSON() ([]byte, error) {
+	r, err := newDiagnosticReport(v.Full, v.Unchanged)
+	if err != nil {
+		return nil, err
+	}
+
+	related := map[DocumentURI]*RelatedDocumentDiagnosticReport{}
+	for uri, rep := range v.RelatedDocuments {
+		rep := rep
+		related[uri] = &rep
+	}
+	if len(related) == 0 {
+		related = nil
+	}
+
+	return json.Marshal(struct {
+		diagnosticReport
+		RelatedDocuments map[DocumentURI]*RelatedDocumentDiagnosticReport `json:"relatedDocuments,omitempty"`
+	}{
+		diagnosticReport: r,
+		RelatedDocuments: related,
+	})
+}
+
+func (v *DocumentDiagnosticReport) UnmarshalJSON(d []byte) error {
+	tmp := struct {
+		diagnosticReport
+		RelatedDocuments map[DocumentURI]RelatedDocumentDiagnosticReport `json:"relatedDocuments"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	full, unchanged, err := tmp.diagnosticReport.split()
+	if err != nil {
+		return err
+	}
+
+	v.Full = full
+	v.Unchanged = unchanged
+	v.RelatedDocuments = tmp.RelatedDocuments
+
+	return nil
+}
+
+// RelatedDocumentDiagnosticReport is the report of a document related to the one requested,
+// which does not have the related documents by itself.
+// Exactly one of Full and Unchanged must be set.
+type RelatedDocumentDiagnosticReport struct {
+	Full      *FullDocumentDiagnosticReport
+	Unchanged *UnchangedDocumentDiagnosticReport
+}
+
+func (v *RelatedDocumentDiagnosticReport) MarshalJSON() ([]byte, error) {
+	r, err := newDiagnosticReport(v.Full, v.Unchanged)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(r)
+}
+
+func (v *RelatedDocumentDiagnosticReport) UnmarshalJSON(d []byte) error {
+	r := diagnosticReport{}
+	if err := json.Unmarshal(d, &r); err != nil {
+		return err
+	}
+
+	full, unchanged, err := r.split()
+	if err != nil {
+		return err
+	}
+
+	v.Full = full
+	v.Unchanged = unchanged
+
+	return nil
+}
+
+type DocumentDiagnosticReportPartialResult struct {
+	RelatedDocuments map[DocumentURI]*RelatedDocumentDiagnosticReport `json:"relatedDocuments"`
+}
+
+// WorkspaceDocumentDiagnosticReport is the report of a document in workspace/diagnostic.
+// Exactly one of Full and Unchanged must be set.
+type WorkspaceDocumentDiagnosticReport struct {
+	URI       DocumentURI
+	Version   *int
+	Full      *FullDocumentDiagnosticReport
+	Unchanged *UnchangedDocumentDiagnosticReport
+}
+
+func (v *WorkspaceDocumentDiagnosticReport) MarshalJSON() ([]byte, error) {
+	r, err := newDiagnosticReport(v.Full, v.Unchanged)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		diagnosticReport
+		URI     DocumentURI `json:"uri"`
+		Version *int        `json:"version"`
+	}{
+		diagnosticReport: r,
+		URI:              v.URI,
+		Version:          v.Version,
+	})
+}
+
+func (v *WorkspaceDocumentDiagnosticReport) UnmarshalJSON(d []byte) error {
+	tmp := struct {
+		diagnosticReport
+		URI     *DocumentURI `json:"uri"`
+		Version *int         `json:"version"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	if tmp.URI == nil {
+		return errors.New("missing uri")
+	}
+
+	full, unchanged, err := tmp.diagnosticReport.split()
+	if err != nil {
+		return err
+	}
+
+	v.URI = *tmp.URI
+	v.Version = tmp.Version
+	v.Full = full
+	v.Unchanged = unchanged
+
+	return nil
+}
+
+type WorkspaceDiagnosticReport struct {
+	Items []WorkspaceDocumentDiagnosticReport `json:"items"`
+}
+
+type WorkspaceDiagnosticReportPartialResult struct {
+	Items []WorkspaceDocumentDiagnosticReport `json:"items"`
+}
+
+type PreviousResultID struct {
+	URI   DocumentURI `json:"uri"`
+	Value string      `json:"value"`
+}
+
+type NotebookCellKind int
+
+const (
+	NotebookCellKindUnknown NotebookCellKind = iota
+	NotebookCellKindMarkup
+	NotebookCellKindCode
+)
+
+type ExecutionSummary struct {
+	ExecutionOrder int   `json:"executionOrder"`
+	Success        *bool `json:"success,omitempty"`
+}
+
+type NotebookCell struct {
+	Kind             NotebookCellKind  `json:"kind"`
+	Document         DocumentURI       `json:"document"`
+	Metadata         interface{}       `json:"metadata,omitempty"`
+	ExecutionSummary *ExecutionSummary `json:"executionSummary,omitempty"`
+}
+
+type NotebookDocument struct {
+	URI          DocumentURI    `json:"uri"`
+	NotebookType string         `json:"notebookType"`
+	Version      int            `json:"version"`
+	Metadata     interface{}    `json:"metadata,omitempty"`
+	Cells        []NotebookCell `json:"cells"`
+}
+
+type NotebookDocumentIdentifier struct {
+	URI DocumentURI `json:"uri"`
+}
+
+type VersionedNotebookDocumentIdentifier struct {
+	Version int         `json:"version"`
+	URI     DocumentURI `json:"uri"`
+}
+
+type NotebookCellArrayChange struct {
+	Start       int            `json:"start"`
+	DeleteCount int            `json:"deleteCount"`
+	Cells       []NotebookCell `json:"cells,omitempty"`
+}
+
+type NotebookDocumentCellChangeStructure struct {
+	Array    NotebookCellArrayChange  `json:"array"`
+	DidOpen  []TextDocumentItem       `json:"didOpen,omitempty"`
+	DidClose []TextDocumentIdentifier `json:"didClose,omitempty"`
+}
+
+type NotebookDocumentCellContentChanges struct {
+	Document VersionedTextDocumentIdentifier  `json:"document"`
+	Changes  []TextDocumentContentChangeEvent `json:"changes"`
+}
+
+type NotebookDocumentCellChanges struct {
+	Structure   *NotebookDocumentCellChangeStructure `json:"structure,omitempty"`
+	Data        []NotebookCell                       `json:"data,omitempty"`
+	TextContent []NotebookDocumentCellContentChanges `json:"textContent,omitempty"`
+}
+
+type NotebookDocumentChangeEvent struct {
+	Metadata interface{}                  `json:"metadata,omitempty"`
+	Cells    *NotebookDocumentCellChanges `json:"cells,omitempty"`
+}
+
+type NotebookDocumentFilter struct {
+	NotebookType string `json:"notebookType,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	Pattern      string `json:"pattern,omitempty"`
+}
+
+// UnmarshalJSON accepts also a string, which matches against the notebook type.
+func (v *NotebookDocumentFilter) UnmarshalJSON(d []byte) error {
+	s := ""
+	if err := json.Unmarshal(d, &s); err == nil {
+		*v = NotebookDocumentFilter{NotebookType: s}
+		return nil
+	}
+
+	tmp := struct {
+		NotebookType string `json:"notebookType,omitempty"`
+		Scheme       string `json:"scheme,omitempty"`
+		Pattern      string `json:"pattern,omitempty"`
+	}{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	v.NotebookType = tmp.NotebookType
+	v.Scheme = tmp.Scheme
+	v.Pattern = tmp.Pattern
+
+	return nil
+}
+
+type NotebookCellLanguage struct {
+	Language string `json:"language"`
+}
+
+type NotebookSelector struct {
+	Notebook *NotebookDocumentFilter `json:"notebook,omitempty"`
+	Cells    []NotebookCellLanguage  `json:"cells,omitempty"`
+}
+
+type FileOperationPatternKind string
+
+const (
+	FileOperationPatternKindFile   FileOperationPatternKind = "file"
+	FileOperationPatternKindFolder FileOperationPatternKind = "folder"
+)
+
+type FileOperationPatternOptions struct {
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+}
+
+type FileOperationPattern struct {
+	Glob    string                       `json:"glob"`
+	Matches FileOperationPatternKind     `json:"matches,omitempty"`
+	Options *FileOperationPatternOptions `json:"options,omitempty"`
+}
+
+type FileOperationFilter struct {
+	Scheme  string               `json:"scheme,omitempty"`
+	Pattern FileOperationPattern `json:"pattern"`
+}
+
+// Match reports whether the filter matches the file or folder at uri.
+// The kind of the resource is ignored if it is empty.
+func (f FileOperationFilter) Match(uri string, kind FileOperationPatternKind) bool {
+	m, err := f.compile()
+	if err != nil {
+		return false
+	}
+
+	return m.match(uri, kind)
+}
+
+// fileOperationMatcher is the FileOperationFilter whose glob pattern is compiled.
+type fileOperationMatcher struct {
+	filter FileOperationFilter
+	glob   *regexp.Regexp
+}
+
+func (f FileOperationFilter) compile() (fileOperationMatcher, error) {
+	pattern := f.Pattern.Glob
+	if f.Pattern.Options != nil && f.Pattern.Options.IgnoreCase {
+		pattern = strings.ToLower(pattern)
+	}
+
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return fileOperationMatcher{}, err
+	}
+
+	return fileOperationMatcher{filter: f, glob: re}, nil
+}
+
+func (m fileOperationMatcher) match(uri string, kind FileOperationPatternKind) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	f := m.filter
+	if f.Scheme != "" && f.Scheme != u.Scheme {
+		return false
+	}
+
+	if f.Pattern.Matches != "" && kind != "" && f.Pattern.Matches != kind {
+		return false
+	}
+
+	path := u.Path
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if f.Pattern.Options != nil && f.Pattern.Options.IgnoreCase {
+		path = strings.ToLower(path)
+	}
+
+	return m.glob.MatchString(path)
+}
+
+type FileCreate struct {
+	URI string `json:"uri"`
+}
+
+type FileRename struct {
+	OldURI string `json:"oldUri"`
+	NewURI string `json:"newUri"`
+}
+
+type FileDelete struct {
+	URI string `json:"uri"`
+}
+
+type ShowDocumentResult struct {
+	Success bool `json:"success"`
+}
+
+type LinkedEditingRanges struct {
+	Ranges      []Range `json:"ranges"`
+	WordPattern string  `json:"wordPattern,omitempty"`
+}
+
+type UniquenessLevel string
+
+const (
+	UniquenessLevelDocument UniquenessLevel = "document"
+	UniquenessLevelProject  UniquenessLevel = "project"
+	UniquenessLevelGroup    UniquenessLevel = "group"
+	UniquenessLevelScheme   UniquenessLevel = "scheme"
+	UniquenessLevelGlobal   UniquenessLevel = "global"
+)
+
+type MonikerKind string
+
+const (
+	MonikerKindImport MonikerKind = "import"
+	MonikerKindExport MonikerKind = "export"
+	MonikerKindLocal  MonikerKind = "local"
+)
+
+type Moniker struct {
+	Scheme     string          `json:"scheme"`
+	Identifier string          `json:"identifier"`
+	Unique     UniquenessLevel `json:"unique"`
+	Kind       MonikerKind     `json:"kind,omitempty"`
+}
+
+type InlineValueText struct {
+	Range Range  `json:"range"`
+	Text  string `json:"text"`
+}
+
+type InlineValueVariableLookup struct {
+	Range               Range  `json:"range"`
+	VariableName        string `json:"variableName,omitempty"`
+	CaseSensitiveLookup bool   `json:"caseSensitiveLookup"`
+}
+
+type InlineValueEvaluatableExpression struct {
+	Range      Range  `json:"range"`
+	Expression string `json:"expression,omitempty"`
+}
+
+// InlineValue is either InlineValueText, InlineValueVariableLookup or InlineValueEvaluatableExpression.
+type InlineValue struct {
+	Text                  *InlineValueText
+	VariableLookup        *InlineValueVariableLookup
+	EvaluatableExpression *InlineValueEvaluatableExpression
+}
+
+func (v *InlineValue) MarshalJSON() ([]byte, error) {
+	switch {
+	case v.Text != nil && v.VariableLookup == nil && v.EvaluatableExpression == nil:
+		return json.Marshal(v.Text)
+	case v.Text == nil && v.VariableLookup != nil && v.EvaluatableExpression == nil:
+		return json.Marshal(v.VariableLookup)
+	case v.Text == nil && v.VariableLookup == nil && v.EvaluatableExpression != nil:
+		return json.Marshal(v.EvaluatableExpression)
+	default:
+		return nil, errors.New("exactly one of text, variable lookup and evaluatable expression should be set")
+	}
+}
+
+func (v *InlineValue) UnmarshalJSON(d []byte) error {
+	tmp := map[string]json.RawMessage{}
+	if err := json.Unmarshal(d, &tmp); err != nil {
+		return err
+	}
+
+	if _, ok := tmp["text"]; ok {
+		t := InlineValueText{}
+		if err := json.Unmarshal(d, &t); err != nil {
+			return err
+		}
+		*v = InlineValue{Text: &t}
+		return nil
+	}
+
+	if _, ok := tmp["caseSensitiveLookup"]; ok {
+		l := InlineValueVariableLookup{}
+		if err := json.Unmarshal(d, &l); err != nil {
+			return err
+		}
+		*v = InlineValue{VariableLookup: &l}
+		return nil
+	}
+
+	e := InlineValueEvaluatableExpression{}
+	if err := json.Unmarshal(d, &e); err != nil {
+		return err
+	}
+	*v = InlineValue{EvaluatableExpression: &e}
+
+	return nil
+}
+
+type InlineValueContext struct {
+	FrameID         int   `json:"frameId"`
+	StoppedLocation Range `json:"stoppedLocation"`
+}
+
+type InlineCompletionTriggerKind int
+
+const (
+	InlineCompletionTriggerKindUnknown InlineCompletionTriggerKind = iota
+	InlineCompletionTriggerKindInvoked
+	InlineCompletionTriggerKindAutomatic
+)
+
+type SelectedCompletionInfo struct {
+	Range Range  `json:"range"`
+	Text  string `json:"text"`
+}
+
+type InlineCompletionContext struct {
+	TriggerKind            InlineCompletionTriggerKind `json:"triggerKind"`
+	SelectedCompletionInfo *SelectedCompletionInfo     `json:"selectedCompletionInfo,omitempty"`
+}
+
+// StringValue is the string with the kind of its format, only "snippet" for now.
+type StringValue struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+func (v *StringValue) MarshalJSON() ([]byte, error) {
+	d := struct {
+		Kind  string `json:"kind"`
+		Value string `json:"value"`
+	}{
+		Kind:  "snippet",
+		Value: v.Value,
+	}
+
+	return json.Marshal(d)
+}
+
+func (v *StringValue) UnmarshalJSON(d []byte) error {
+	tmp := struct {
+		Kind  string `json:"kind"`
+		Value string `json:"value"`
+	}{}
+
+	err := json.Unmarshal(d, &tmp)
+	if err != nil {
+		return err
+	}
+
+	if tmp.Kind != "snippet" {
+		return errors.New("invalid kind")
+	}
+
+	v.Kind = tmp.Kind
+	v.Value = tmp.Value
+
+	return nil
+}
+
+// InlineCompletionText is string | StringValue.
+// It is encoded as the snippet if Snippet is not nil, Value otherwise.
+type InlineCompletionText struct {
+	Value   string
+	Snippet *StringValue
+}
+
+func (v *InlineCompletionText) MarshalJSON() ([]byte, error) {
+	if v.Snippet != nil {
+		if v.Value != "" {
+			return nil, errors.New("both value and snippet are set")
+		}
+		return json.Marshal(v.Snippet)
+	}
+
+	return json.Marshal(v.Value)
+}
+
+func (v *InlineCompletionText) UnmarshalJSON(d []byte) error {
+	s := ""
+	if err := json.Unmarshal(d, &s); err == nil {
+		v.Value = s
+		v.Snippet = nil
+		return nil
+	}
+
+	snippet := StringValue{}
+	if err := json.Unmarshal(d, &snippet); err != nil {
+		return err
+	}
+	v.Value = ""
+	v.Snippet = &snippet
+
+	return nil
+}
+
+type InlineCompletionItem struct {
+	InsertText InlineCompletionText `json:"insertText"`
+	FilterText string               `json:"filterText,omitempty"`
+	Range      *Range               `json:"range,omitempty"`
+	Command    *Command             `json:"command,omitempty"`
+}
+
+type InlineCompletionList struct {
+	Items []InlineCompletionItem `json:"items"`
+}
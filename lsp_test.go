@@ -490,6 +490,160 @@ func TestLocationLink_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
+func TestDefinitionResult_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DefinitionResult
+		json   string
+	}{
+		{
+			goType: lsp.DefinitionResult{
+				Locations: []lsp.Location{
+					{URI: "uri", Range: lineRange(1, 2, 3)},
+				},
+			},
+			json: `[{"uri":"uri","range":{"start":{"line":1,"character":2},"end":{"line":1,"character":3}}}]`,
+		},
+		{
+			goType: lsp.DefinitionResult{
+				LocationLinks: []lsp.LocationLink{
+					{
+						TargetURI:            "uri",
+						TargetRange:          lineRange(1, 0, 5),
+						TargetSelectionRange: lineRange(1, 2, 3),
+					},
+				},
+			},
+			json: `[{"targetUri":"uri","targetRange":{"start":{"line":1,"character":0},"end":{"line":1,"character":5}},"targetSelectionRange":{"start":{"line":1,"character":2},"end":{"line":1,"character":3}}}]`,
+		},
+		{
+			goType: lsp.DefinitionResult{
+				Locations: []lsp.Location{},
+			},
+			json: `[]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.DefinitionResult{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefinitionResult_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input string
+		want  lsp.DefinitionResult
+		err   bool
+	}{
+		{
+			input: `{"uri":"uri","range":{"start":{"line":1,"character":2},"end":{"line":1,"character":3}}}`,
+			want: lsp.DefinitionResult{
+				Locations: []lsp.Location{
+					{URI: "uri", Range: lineRange(1, 2, 3)},
+				},
+			},
+		},
+		{
+			input: `null`,
+			want:  lsp.DefinitionResult{},
+		},
+		{
+			input: `"uri"`,
+			want:  lsp.DefinitionResult{},
+			err:   true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.DefinitionResult{}
+
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if !tt.err && err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("should be error but not")
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefinitionResult_WithLinkSupport(t *testing.T) {
+	links := lsp.DefinitionResult{
+		LocationLinks: []lsp.LocationLink{
+			{
+				TargetURI:            "uri",
+				TargetRange:          lineRange(1, 0, 5),
+				TargetSelectionRange: lineRange(1, 2, 3),
+			},
+		},
+	}
+
+	cases := []struct {
+		input       lsp.DefinitionResult
+		linkSupport bool
+		want        lsp.DefinitionResult
+	}{
+		{
+			input:       links,
+			linkSupport: true,
+			want:        links,
+		},
+		{
+			input:       links,
+			linkSupport: false,
+			want: lsp.DefinitionResult{
+				Locations: []lsp.Location{
+					{URI: "uri", Range: lineRange(1, 2, 3)},
+				},
+			},
+		},
+		{
+			input: lsp.DefinitionResult{
+				Locations: []lsp.Location{
+					{URI: "uri", Range: lineRange(1, 2, 3)},
+				},
+			},
+			linkSupport: false,
+			want: lsp.DefinitionResult{
+				Locations: []lsp.Location{
+					{URI: "uri", Range: lineRange(1, 2, 3)},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := tt.input.WithLinkSupport(tt.linkSupport)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDiagnostic_MarshalUnmarshal(t *testing.T) {
 	testIntCode := lsp.NewInt(1)
 	testStringCode := lsp.NewString("code")
@@ -1623,202 +1777,75 @@ func TestDeleteFile_Unmarshal(t *testing.T) {
 }
 
 func TestDocumentChanges_MarshalUnmarshal(t *testing.T) {
+	textDocumentEdit := &lsp.TextDocumentEdit{
+		TextDocument: lsp.VersionedTextDocumentIdentifier{},
+		Edits: []lsp.TextEdit{
+			{
+				Range:   lsp.Range{},
+				NewText: "text",
+			},
+		},
+	}
+	createFile := &lsp.CreateFile{
+		Kind: "create",
+		URI:  "uri",
+		Options: &lsp.CreateFileOptions{
+			Overwrite:      true,
+			IgnoreIfExists: true,
+		},
+	}
+	renameFile := &lsp.RenameFile{
+		Kind:   "rename",
+		OldURI: "old-uri",
+		NewURI: "new-uri",
+		Options: &lsp.RenameFileOptions{
+			Overwrite:      true,
+			IgnoreIfExists: true,
+		},
+	}
+	deleteFile := &lsp.DeleteFile{
+		Kind: "delete",
+		URI:  "uri",
+		Options: &lsp.DeleteFileOptions{
+			Recursive:         true,
+			IgnoreIfNotExists: true,
+		},
+	}
+
+	textDocumentEditJSON := `{"textDocument":{"uri":"","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]}`
+	createFileJSON := `{"kind":"create","uri":"uri","options":{"overwrite":true,"ignoreIfExists":true}}`
+	renameFileJSON := `{"kind":"rename","oldUri":"old-uri","newUri":"new-uri","options":{"overwrite":true,"ignoreIfExists":true}}`
+	deleteFileJSON := `{"kind":"delete","uri":"uri","options":{"recursive":true,"ignoreIfNotExists":true}}`
+
 	cases := []struct {
 		goType lsp.DocumentChanges
 		json   string
 	}{
 		{
 			goType: lsp.DocumentChanges{
-				TextDocumentEdits: []lsp.TextDocumentEdit{
-					{
-						TextDocument: lsp.VersionedTextDocumentIdentifier{},
-						Edits: []lsp.TextEdit{
-							{
-								Range:   lsp.Range{},
-								NewText: "text",
-							},
-						},
-					},
-				},
-				CreateFiles: []lsp.CreateFile{
-					{
-						Kind: "create",
-						URI:  "uri",
-						Options: &lsp.CreateFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				RenameFiles: []lsp.RenameFile{
-					{
-						Kind:   "rename",
-						OldURI: "old-uri",
-						NewURI: "new-uri",
-						Options: &lsp.RenameFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				DeleteFiles: []lsp.DeleteFile{
-					{
-						Kind: "delete",
-						URI:  "uri",
-						Options: &lsp.DeleteFileOptions{
-							Recursive:         true,
-							IgnoreIfNotExists: true,
-						},
-					},
-				},
-			},
-			json: `[{"textDocument":{"uri":"","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]},{"kind":"create","uri":"uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"rename","oldUri":"old-uri","newUri":"new-uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"delete","uri":"uri","options":{"recursive":true,"ignoreIfNotExists":true}}]`,
-		},
-		{
-			goType: lsp.DocumentChanges{
-				CreateFiles: []lsp.CreateFile{
-					{
-						Kind: "create",
-						URI:  "uri",
-						Options: &lsp.CreateFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				RenameFiles: []lsp.RenameFile{
-					{
-						Kind:   "rename",
-						OldURI: "old-uri",
-						NewURI: "new-uri",
-						Options: &lsp.RenameFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				DeleteFiles: []lsp.DeleteFile{
-					{
-						Kind: "delete",
-						URI:  "uri",
-						Options: &lsp.DeleteFileOptions{
-							Recursive:         true,
-							IgnoreIfNotExists: true,
-						},
-					},
-				},
-			},
-			json: `[{"kind":"create","uri":"uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"rename","oldUri":"old-uri","newUri":"new-uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"delete","uri":"uri","options":{"recursive":true,"ignoreIfNotExists":true}}]`,
-		},
-		{
-			goType: lsp.DocumentChanges{
-				TextDocumentEdits: []lsp.TextDocumentEdit{
-					{
-						TextDocument: lsp.VersionedTextDocumentIdentifier{},
-						Edits: []lsp.TextEdit{
-							{
-								Range:   lsp.Range{},
-								NewText: "text",
-							},
-						},
-					},
-				},
-				RenameFiles: []lsp.RenameFile{
-					{
-						Kind:   "rename",
-						OldURI: "old-uri",
-						NewURI: "new-uri",
-						Options: &lsp.RenameFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				DeleteFiles: []lsp.DeleteFile{
-					{
-						Kind: "delete",
-						URI:  "uri",
-						Options: &lsp.DeleteFileOptions{
-							Recursive:         true,
-							IgnoreIfNotExists: true,
-						},
-					},
-				},
+				{TextDocumentEdit: textDocumentEdit},
+				{CreateFile: createFile},
+				{RenameFile: renameFile},
+				{DeleteFile: deleteFile},
 			},
-			json: `[{"textDocument":{"uri":"","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]},{"kind":"rename","oldUri":"old-uri","newUri":"new-uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"delete","uri":"uri","options":{"recursive":true,"ignoreIfNotExists":true}}]`,
+			json: `[` + textDocumentEditJSON + `,` + createFileJSON + `,` + renameFileJSON + `,` + deleteFileJSON + `]`,
 		},
 		{
 			goType: lsp.DocumentChanges{
-				TextDocumentEdits: []lsp.TextDocumentEdit{
-					{
-						TextDocument: lsp.VersionedTextDocumentIdentifier{},
-						Edits: []lsp.TextEdit{
-							{
-								Range:   lsp.Range{},
-								NewText: "text",
-							},
-						},
-					},
-				},
-				CreateFiles: []lsp.CreateFile{
-					{
-						Kind: "create",
-						URI:  "uri",
-						Options: &lsp.CreateFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				DeleteFiles: []lsp.DeleteFile{
-					{
-						Kind: "delete",
-						URI:  "uri",
-						Options: &lsp.DeleteFileOptions{
-							Recursive:         true,
-							IgnoreIfNotExists: true,
-						},
-					},
-				},
+				{RenameFile: renameFile},
+				{TextDocumentEdit: textDocumentEdit},
+				{DeleteFile: deleteFile},
+				{CreateFile: createFile},
 			},
-			json: `[{"textDocument":{"uri":"","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]},{"kind":"create","uri":"uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"delete","uri":"uri","options":{"recursive":true,"ignoreIfNotExists":true}}]`,
+			json: `[` + renameFileJSON + `,` + textDocumentEditJSON + `,` + deleteFileJSON + `,` + createFileJSON + `]`,
 		},
 		{
 			goType: lsp.DocumentChanges{
-				TextDocumentEdits: []lsp.TextDocumentEdit{
-					{
-						TextDocument: lsp.VersionedTextDocumentIdentifier{},
-						Edits: []lsp.TextEdit{
-							{
-								Range:   lsp.Range{},
-								NewText: "text",
-							},
-						},
-					},
-				},
-				CreateFiles: []lsp.CreateFile{
-					{
-						Kind: "create",
-						URI:  "uri",
-						Options: &lsp.CreateFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
-				RenameFiles: []lsp.RenameFile{
-					{
-						Kind:   "rename",
-						OldURI: "old-uri",
-						NewURI: "new-uri",
-						Options: &lsp.RenameFileOptions{
-							Overwrite:      true,
-							IgnoreIfExists: true,
-						},
-					},
-				},
+				{TextDocumentEdit: textDocumentEdit},
+				{DeleteFile: deleteFile},
+				{TextDocumentEdit: textDocumentEdit},
 			},
-			json: `[{"textDocument":{"uri":"","version":null},"edits":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]},{"kind":"create","uri":"uri","options":{"overwrite":true,"ignoreIfExists":true}},{"kind":"rename","oldUri":"old-uri","newUri":"new-uri","options":{"overwrite":true,"ignoreIfExists":true}}]`,
+			json: `[` + textDocumentEditJSON + `,` + deleteFileJSON + `,` + textDocumentEditJSON + `]`,
 		},
 		{
 			goType: lsp.DocumentChanges{},
@@ -1849,6 +1876,67 @@ func TestDocumentChanges_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
+func TestDocumentChanges_Marshal(t *testing.T) {
+	cases := []struct {
+		input lsp.DocumentChanges
+	}{
+		{
+			input: lsp.DocumentChanges{{}},
+		},
+		{
+			input: lsp.DocumentChanges{
+				{
+					CreateFile: &lsp.CreateFile{URI: "uri"},
+					DeleteFile: &lsp.DeleteFile{URI: "uri"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			if _, err := json.Marshal(&tt.input); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
+func TestDocumentChanges_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input string
+	}{
+		{
+			input: `[{}]`,
+		},
+		{
+			input: `[{"uri":"uri"}]`,
+		},
+		{
+			input: `[{"kind":"hoge","uri":"uri"}]`,
+		},
+		{
+			input: `[{"kind":"create"}]`,
+		},
+		{
+			input: `[{"kind":"rename","oldUri":"old-uri"}]`,
+		},
+		{
+			input: `[{"textDocument":{"uri":"uri","version":null}}]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.DocumentChanges{}
+
+			if err := json.Unmarshal([]byte(tt.input), &got); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
 func TestTextDocumentIdentifier_MarshalUnmarshal(t *testing.T) {
 	cases := []struct {
 		goType lsp.TextDocumentIdentifier
@@ -2144,19 +2232,138 @@ func TestMarkupContent_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
-func TestWorkDoneProgressBegin_MarshalUnmarshal(t *testing.T) {
+func TestMarkupUnion_Marshal(t *testing.T) {
 	cases := []struct {
-		goType lsp.WorkDoneProgressBegin
-		json   string
+		input lsp.MarkupUnion
 	}{
 		{
-			goType: lsp.WorkDoneProgressBegin{
-				Kind:        "begin",
-				Title:       "title",
-				Cancellable: true,
-				Message:     "message",
-				Percentage:  10,
-			},
+			input: lsp.MarkupUnion{},
+		},
+		{
+			input: lsp.MarkupUnion{
+				String:        strPtr("value"),
+				MarkupContent: &lsp.MarkupContent{Kind: lsp.MarkupKindPlainText},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			if _, err := json.Marshal(&tt.input); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
+func TestMarkupUnion_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input string
+	}{
+		{
+			input: `{}`,
+		},
+		{
+			input: `{"value":"value"}`,
+		},
+		{
+			input: `1`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.MarkupUnion{}
+
+			if err := json.Unmarshal([]byte(tt.input), &got); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
+func TestMarkupUnion_WithFormats(t *testing.T) {
+	markdown := lsp.MarkupUnion{
+		MarkupContent: &lsp.MarkupContent{
+			Kind:  lsp.MarkupKindMarkdown,
+			Value: "# Title\n\nUse `foo\\_bar`.\n\n```go\nfunc foo() {}\n```",
+		},
+	}
+
+	cases := []struct {
+		input   lsp.MarkupUnion
+		formats []lsp.MarkupKind
+		want    lsp.MarkupUnion
+	}{
+		{
+			input:   markdown,
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText, lsp.MarkupKindMarkdown},
+			want:    markdown,
+		},
+		{
+			input:   markdown,
+			formats: nil,
+			want:    markdown,
+		},
+		{
+			input:   markdown,
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want: lsp.MarkupUnion{
+				MarkupContent: &lsp.MarkupContent{
+					Kind:  lsp.MarkupKindPlainText,
+					Value: "Title\n\nUse foo_bar.\n\nfunc foo() {}",
+				},
+			},
+		},
+		{
+			input: lsp.MarkupUnion{
+				MarkedStrings: []lsp.MarkedString{
+					{Value: "**bold**"},
+					{Language: "go", Value: "var `x` int"},
+				},
+			},
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want: lsp.MarkupUnion{
+				MarkupContent: &lsp.MarkupContent{
+					Kind:  lsp.MarkupKindPlainText,
+					Value: "**bold**\n\nvar `x` int",
+				},
+			},
+		},
+		{
+			input: lsp.MarkupUnion{
+				String: strPtr("`value`"),
+			},
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want: lsp.MarkupUnion{
+				String: strPtr("`value`"),
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := tt.input.WithFormats(tt.formats)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkDoneProgressBegin_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.WorkDoneProgressBegin
+		json   string
+	}{
+		{
+			goType: lsp.WorkDoneProgressBegin{
+				Kind:        "begin",
+				Title:       "title",
+				Cancellable: true,
+				Message:     "message",
+				Percentage:  10,
+			},
 			json: `{"kind":"begin","title":"title","cancellable":true,"message":"message","percentage":10}`,
 		},
 		{
@@ -3154,7 +3361,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3183,7 +3390,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3211,7 +3418,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3236,7 +3443,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3264,7 +3471,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3292,7 +3499,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:              "detail",
-				Documentation:       "documentation",
+				Documentation:       &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:          true,
 				Preselect:           true,
 				SortText:            "sort-text",
@@ -3317,7 +3524,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:        "detail",
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:    true,
 				Preselect:     true,
 				SortText:      "sort-text",
@@ -3345,7 +3552,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3373,7 +3580,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3401,7 +3608,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				FilterText:       "filter-text",
@@ -3429,7 +3636,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				SortText:         "sort-text",
 				FilterText:       "filter-text",
@@ -3457,7 +3664,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Preselect:        true,
 				SortText:         "sort-text",
 				FilterText:       "filter-text",
@@ -3512,7 +3719,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 				Tags: []lsp.CompletionItemTag{
 					lsp.CompletionItemTagDeprecated,
 				},
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3538,7 +3745,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 				Label:            "label",
 				Kind:             lsp.CompletionItemKindClass,
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3566,7 +3773,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3594,7 +3801,7 @@ func TestCompletionItem_MarshalUnmarshal(t *testing.T) {
 					lsp.CompletionItemTagDeprecated,
 				},
 				Detail:           "detail",
-				Documentation:    "documentation",
+				Documentation:    &lsp.MarkupUnion{String: strPtr("documentation")},
 				Deprecated:       true,
 				Preselect:        true,
 				SortText:         "sort-text",
@@ -3651,26 +3858,45 @@ func TestHover_MarshalUnmarshal(t *testing.T) {
 	}{
 		{
 			goType: lsp.Hover{
-				Contents: lsp.MarkupContent{},
-				Range:    lsp.Range{},
+				Contents: lsp.MarkupUnion{
+					MarkupContent: &lsp.MarkupContent{
+						Kind:  lsp.MarkupKindMarkdown,
+						Value: "value",
+					},
+				},
+				Range: &lsp.Range{},
 			},
-			json: `{"contents":{"kind":"","value":""},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+			json: `{"contents":{"kind":"markdown","value":"value"},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
 		},
 		{
 			goType: lsp.Hover{
-				Contents: lsp.MarkupContent{},
+				Contents: lsp.MarkupUnion{
+					String: strPtr("value"),
+				},
 			},
-			json: `{"contents":{"kind":"","value":""},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+			json: `{"contents":"value"}`,
 		},
 		{
 			goType: lsp.Hover{
-				Range: lsp.Range{},
+				Contents: lsp.MarkupUnion{
+					MarkedString: &lsp.MarkedString{
+						Language: "go",
+						Value:    "value",
+					},
+				},
 			},
-			json: `{"contents":{"kind":"","value":""},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+			json: `{"contents":{"language":"go","value":"value"}}`,
 		},
 		{
-			goType: lsp.Hover{},
-			json:   `{"contents":{"kind":"","value":""},"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+			goType: lsp.Hover{
+				Contents: lsp.MarkupUnion{
+					MarkedStrings: []lsp.MarkedString{
+						{Value: "value"},
+						{Language: "go", Value: "value"},
+					},
+				},
+			},
+			json: `{"contents":["value",{"language":"go","value":"value"}]}`,
 		},
 	}
 
@@ -3843,7 +4069,7 @@ func TestSignatureInformation_MarshalUnmarshal(t *testing.T) {
 		{
 			goType: lsp.SignatureInformation{
 				Label:         "label",
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 				Parameters:    []lsp.ParameterInformation{{}},
 			},
 			json: `{"label":"label","documentation":"documentation","parameters":[{"label":null}]}`,
@@ -3851,7 +4077,7 @@ func TestSignatureInformation_MarshalUnmarshal(t *testing.T) {
 		{
 			goType: lsp.SignatureInformation{
 				Label:         "label",
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 			},
 			json: `{"label":"label","documentation":"documentation"}`,
 		},
@@ -3864,7 +4090,7 @@ func TestSignatureInformation_MarshalUnmarshal(t *testing.T) {
 		},
 		{
 			goType: lsp.SignatureInformation{
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 				Parameters:    []lsp.ParameterInformation{{}},
 			},
 			json: `{"label":"","documentation":"documentation","parameters":[{"label":null}]}`,
@@ -3906,7 +4132,7 @@ func TestParameterInformation_MarshalUnmarshal(t *testing.T) {
 		{
 			goType: lsp.ParameterInformation{
 				Label:         "label",
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 			},
 			json: `{"label":"label","documentation":"documentation"}`,
 		},
@@ -3918,7 +4144,7 @@ func TestParameterInformation_MarshalUnmarshal(t *testing.T) {
 		},
 		{
 			goType: lsp.ParameterInformation{
-				Documentation: "documentation",
+				Documentation: &lsp.MarkupUnion{String: strPtr("documentation")},
 			},
 			json: `{"label":null,"documentation":"documentation"}`,
 		},
@@ -4256,6 +4482,171 @@ func TestSymbolInformation_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
+func TestDocumentSymbolResult_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DocumentSymbolResult
+		json   string
+	}{
+		{
+			goType: lsp.DocumentSymbolResult{
+				DocumentSymbols: []lsp.DocumentSymbol{
+					{
+						Name:           "name",
+						Kind:           lsp.SymbolKindFunction,
+						Range:          lineRange(0, 0, 10),
+						SelectionRange: lineRange(0, 5, 9),
+					},
+				},
+			},
+			json: `[{"name":"name","kind":12,"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":10}},"selectionRange":{"start":{"line":0,"character":5},"end":{"line":0,"character":9}}}]`,
+		},
+		{
+			goType: lsp.DocumentSymbolResult{
+				SymbolInformation: []lsp.SymbolInformation{
+					{
+						Name: "name",
+						Kind: lsp.SymbolKindFunction,
+						Location: lsp.Location{
+							URI:   "uri",
+							Range: lineRange(0, 0, 10),
+						},
+					},
+				},
+			},
+			json: `[{"name":"name","kind":12,"location":{"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":10}}}}]`,
+		},
+		{
+			goType: lsp.DocumentSymbolResult{
+				DocumentSymbols: []lsp.DocumentSymbol{},
+			},
+			json: `[]`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.DocumentSymbolResult{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewDocumentSymbolResult(t *testing.T) {
+	symbols := []lsp.DocumentSymbol{
+		{
+			Name:           "T",
+			Kind:           lsp.SymbolKindStruct,
+			Range:          lineRange(0, 0, 20),
+			SelectionRange: lineRange(0, 5, 6),
+			Children: []lsp.DocumentSymbol{
+				{
+					Name:           "Field",
+					Kind:           lsp.SymbolKindField,
+					Range:          lineRange(0, 10, 19),
+					SelectionRange: lineRange(0, 10, 15),
+				},
+			},
+		},
+		{
+			Name:           "Op",
+			Kind:           lsp.SymbolKindOperator,
+			Range:          lineRange(1, 0, 10),
+			SelectionRange: lineRange(1, 0, 2),
+		},
+	}
+
+	cases := []struct {
+		caps *lsp.DocumentSymbolClientCapabilities
+		want lsp.DocumentSymbolResult
+	}{
+		{
+			caps: nil,
+			want: lsp.DocumentSymbolResult{
+				SymbolInformation: []lsp.SymbolInformation{
+					{
+						Name:     "T",
+						Kind:     lsp.SymbolKindClass,
+						Location: lsp.Location{URI: "uri", Range: lineRange(0, 0, 20)},
+					},
+					{
+						Name:          "Field",
+						Kind:          lsp.SymbolKindField,
+						Location:      lsp.Location{URI: "uri", Range: lineRange(0, 10, 19)},
+						ContainerName: "T",
+					},
+					{
+						Name:     "Op",
+						Kind:     lsp.SymbolKindMethod,
+						Location: lsp.Location{URI: "uri", Range: lineRange(1, 0, 10)},
+					},
+				},
+			},
+		},
+		{
+			caps: &lsp.DocumentSymbolClientCapabilities{
+				HierarchicalDocumentSymbolSupport: true,
+				SymbolKind: &struct {
+					ValueSet []lsp.SymbolKind `json:"valueSet,omitempty"`
+				}{
+					ValueSet: []lsp.SymbolKind{
+						lsp.SymbolKindStruct,
+						lsp.SymbolKindVariable,
+						lsp.SymbolKindFunction,
+					},
+				},
+			},
+			want: lsp.DocumentSymbolResult{
+				DocumentSymbols: []lsp.DocumentSymbol{
+					{
+						Name:           "T",
+						Kind:           lsp.SymbolKindStruct,
+						Range:          lineRange(0, 0, 20),
+						SelectionRange: lineRange(0, 5, 6),
+						Children: []lsp.DocumentSymbol{
+							{
+								Name:           "Field",
+								Kind:           lsp.SymbolKindVariable,
+								Range:          lineRange(0, 10, 19),
+								SelectionRange: lineRange(0, 10, 15),
+							},
+						},
+					},
+					{
+						Name:           "Op",
+						Kind:           lsp.SymbolKindFunction,
+						Range:          lineRange(1, 0, 10),
+						SelectionRange: lineRange(1, 0, 2),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.NewDocumentSymbolResult("uri", symbols, tt.caps)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestCodeActionContext_MarshalUnmarshal(t *testing.T) {
 	cases := []struct {
 		goType lsp.CodeActionContext
@@ -4414,43 +4805,40 @@ func TestCodeAction_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
-func TestCodeLens_MarshalUnmarshal(t *testing.T) {
+func TestCodeActionOrCommand_MarshalUnmarshal(t *testing.T) {
 	cases := []struct {
-		goType lsp.CodeLens
+		goType lsp.CodeActionOrCommand
 		json   string
 	}{
 		{
-			goType: lsp.CodeLens{
-				Range:   lsp.Range{},
-				Command: &lsp.Command{},
-				Data:    float64(1),
-			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""},"data":1}`,
-		},
-		{
-			goType: lsp.CodeLens{
-				Range:   lsp.Range{},
-				Command: &lsp.Command{},
+			goType: lsp.CodeActionOrCommand{
+				Command: &lsp.Command{
+					Title:   "title",
+					Command: "command",
+				},
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""}}`,
+			json: `{"title":"title","command":"command"}`,
 		},
 		{
-			goType: lsp.CodeLens{
-				Range: lsp.Range{},
-				Data:  float64(1),
+			goType: lsp.CodeActionOrCommand{
+				CodeAction: &lsp.CodeAction{
+					Title: "title",
+					Kind:  lsp.CodeActionKindQuickFix,
+					Command: &lsp.Command{
+						Title:   "title",
+						Command: "command",
+					},
+				},
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"data":1}`,
+			json: `{"title":"title","kind":"quickfix","command":{"title":"title","command":"command"}}`,
 		},
 		{
-			goType: lsp.CodeLens{
-				Command: &lsp.Command{},
-				Data:    float64(1),
+			goType: lsp.CodeActionOrCommand{
+				CodeAction: &lsp.CodeAction{
+					Title: "title",
+				},
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""},"data":1}`,
-		},
-		{
-			goType: lsp.CodeLens{},
-			json:   `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+			json: `{"title":"title"}`,
 		},
 	}
 
@@ -4464,7 +4852,7 @@ func TestCodeLens_MarshalUnmarshal(t *testing.T) {
 				t.Fatalf("mismatch (-want +got):\n%s", diff)
 			}
 
-			gotGoType := lsp.CodeLens{}
+			gotGoType := lsp.CodeActionOrCommand{}
 
 			err = json.Unmarshal(gotJSON, &gotGoType)
 			if err != nil {
@@ -4477,46 +4865,294 @@ func TestCodeLens_MarshalUnmarshal(t *testing.T) {
 	}
 }
 
-func TestDocumentLink_MarshalUnmarshal(t *testing.T) {
-	cases := []struct {
-		goType lsp.DocumentLink
-		json   string
-	}{
-		{
-			goType: lsp.DocumentLink{
-				Range:   lsp.Range{},
-				Target:  "target",
-				Tooltip: "tooltip",
-				Data:    float64(1),
-			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","tooltip":"tooltip","data":1}`,
+func TestCodeActionResult_Filter(t *testing.T) {
+	edit := &lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+			"uri": {{NewText: "text"}},
 		},
+	}
+	command := &lsp.Command{Title: "command", Command: "command"}
+
+	result := lsp.CodeActionResult{
+		{Command: command},
 		{
-			goType: lsp.DocumentLink{
-				Range:   lsp.Range{},
-				Target:  "target",
-				Tooltip: "tooltip",
+			CodeAction: &lsp.CodeAction{
+				Title:       "fix",
+				Kind:        lsp.CodeActionKindQuickFix,
+				IsPreferred: true,
+				Edit:        edit,
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","tooltip":"tooltip"}`,
 		},
 		{
-			goType: lsp.DocumentLink{
-				Range:  lsp.Range{},
-				Target: "target",
-				Data:   float64(1),
+			CodeAction: &lsp.CodeAction{
+				Title:   "extract",
+				Kind:    lsp.CodeActionKindRefactorExtract,
+				Edit:    edit,
+				Command: command,
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","data":1}`,
 		},
 		{
-			goType: lsp.DocumentLink{
-				Range:   lsp.Range{},
-				Tooltip: "tooltip",
-				Data:    float64(1),
+			CodeAction: &lsp.CodeAction{
+				Title:   "organize",
+				Kind:    lsp.CodeActionKindSourceOrganizeImports,
+				Command: command,
 			},
-			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"tooltip":"tooltip","data":1}`,
 		},
+	}
+
+	literalSupport := func(kinds ...lsp.CodeActionKind) *lsp.CodeActionClientCapabilities {
+		caps := &lsp.CodeActionClientCapabilities{}
+		caps.CodeActionLiteralSupport = &struct {
+			CodeActionKind *struct {
+				ValueSet []lsp.CodeActionKind `json:"valueSet,omitempty"`
+			} `json:"codeActionKind,omitempty"`
+		}{
+			CodeActionKind: &struct {
+				ValueSet []lsp.CodeActionKind `json:"valueSet,omitempty"`
+			}{
+				ValueSet: kinds,
+			},
+		}
+		return caps
+	}
+
+	cases := []struct {
+		only []lsp.CodeActionKind
+		caps *lsp.CodeActionClientCapabilities
+		want lsp.CodeActionResult
+	}{
 		{
-			goType: lsp.DocumentLink{
+			caps: nil,
+			want: lsp.CodeActionResult{
+				{Command: command},
+				{
+					Command: &lsp.Command{
+						Title:     "fix",
+						Command:   lsp.CodeActionApplyEditCommand,
+						Arguments: []interface{}{edit},
+					},
+				},
+				{
+					Command: &lsp.Command{
+						Title:     "extract",
+						Command:   lsp.CodeActionApplyEditCommand,
+						Arguments: []interface{}{edit, command},
+					},
+				},
+				{Command: command},
+			},
+		},
+		{
+			only: []lsp.CodeActionKind{lsp.CodeActionKindRefactor},
+			caps: literalSupport(),
+			want: lsp.CodeActionResult{
+				{
+					CodeAction: &lsp.CodeAction{
+						Title:   "extract",
+						Kind:    lsp.CodeActionKindRefactorExtract,
+						Edit:    edit,
+						Command: command,
+					},
+				},
+			},
+		},
+		{
+			caps: literalSupport(lsp.CodeActionKindQuickFix, lsp.CodeActionKindSource),
+			want: lsp.CodeActionResult{
+				{Command: command},
+				{
+					CodeAction: &lsp.CodeAction{
+						Title: "fix",
+						Kind:  lsp.CodeActionKindQuickFix,
+						Edit:  edit,
+					},
+				},
+				{
+					CodeAction: &lsp.CodeAction{
+						Title:   "organize",
+						Kind:    lsp.CodeActionKindSourceOrganizeImports,
+						Command: command,
+					},
+				},
+			},
+		},
+		{
+			caps: func() *lsp.CodeActionClientCapabilities {
+				caps := literalSupport(lsp.CodeActionKindQuickFix)
+				caps.IsPreferredSupport = true
+				return caps
+			}(),
+			want: lsp.CodeActionResult{
+				{Command: command},
+				{
+					CodeAction: &lsp.CodeAction{
+						Title:       "fix",
+						Kind:        lsp.CodeActionKindQuickFix,
+						IsPreferred: true,
+						Edit:        edit,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := result.Filter(tt.only, tt.caps)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyWorkspaceEditResponse_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.ApplyWorkspaceEditResponse
+		json   string
+	}{
+		{
+			goType: lsp.ApplyWorkspaceEditResponse{
+				Applied:       false,
+				FailureReason: "reason",
+				FailedChange:  intPtr(1),
+			},
+			json: `{"applied":false,"failureReason":"reason","failedChange":1}`,
+		},
+		{
+			goType: lsp.ApplyWorkspaceEditResponse{
+				Applied: true,
+			},
+			json: `{"applied":true}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.ApplyWorkspaceEditResponse{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCodeLens_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.CodeLens
+		json   string
+	}{
+		{
+			goType: lsp.CodeLens{
+				Range:   lsp.Range{},
+				Command: &lsp.Command{},
+				Data:    float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""},"data":1}`,
+		},
+		{
+			goType: lsp.CodeLens{
+				Range:   lsp.Range{},
+				Command: &lsp.Command{},
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""}}`,
+		},
+		{
+			goType: lsp.CodeLens{
+				Range: lsp.Range{},
+				Data:  float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"data":1}`,
+		},
+		{
+			goType: lsp.CodeLens{
+				Command: &lsp.Command{},
+				Data:    float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"command":{"title":"","command":""},"data":1}`,
+		},
+		{
+			goType: lsp.CodeLens{},
+			json:   `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.CodeLens{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentLink_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DocumentLink
+		json   string
+	}{
+		{
+			goType: lsp.DocumentLink{
+				Range:   lsp.Range{},
+				Target:  "target",
+				Tooltip: "tooltip",
+				Data:    float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","tooltip":"tooltip","data":1}`,
+		},
+		{
+			goType: lsp.DocumentLink{
+				Range:   lsp.Range{},
+				Target:  "target",
+				Tooltip: "tooltip",
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","tooltip":"tooltip"}`,
+		},
+		{
+			goType: lsp.DocumentLink{
+				Range:  lsp.Range{},
+				Target: "target",
+				Data:   float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"target":"target","data":1}`,
+		},
+		{
+			goType: lsp.DocumentLink{
+				Range:   lsp.Range{},
+				Tooltip: "tooltip",
+				Data:    float64(1),
+			},
+			json: `{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"tooltip":"tooltip","data":1}`,
+		},
+		{
+			goType: lsp.DocumentLink{
 				Target:  "target",
 				Tooltip: "tooltip",
 				Data:    float64(1),
@@ -4898,3 +5534,966 @@ func TestSelectionRange_MarshalUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestSemanticTokens_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.SemanticTokens
+		json   string
+	}{
+		{
+			goType: lsp.SemanticTokens{ResultID: "1", Data: []int{0, 1, 2, 3, 0}},
+			json:   `{"resultId":"1","data":[0,1,2,3,0]}`,
+		},
+		{
+			goType: lsp.SemanticTokens{Data: []int{}},
+			json:   `{"data":[]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.SemanticTokens{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensDelta_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.SemanticTokensDelta
+		json   string
+	}{
+		{
+			goType: lsp.SemanticTokensDelta{ResultID: "2", Edits: []lsp.SemanticTokensEdit{{Start: 5, DeleteCount: 5, Data: []int{1, 2, 3, 4, 0}}, {Start: 10, DeleteCount: 5}}},
+			json:   `{"resultId":"2","edits":[{"start":5,"deleteCount":5,"data":[1,2,3,4,0]},{"start":10,"deleteCount":5}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.SemanticTokensDelta{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensFull_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.SemanticTokensFull
+		json   string
+	}{
+		{
+			goType: lsp.SemanticTokensFullUnsupported,
+			json:   `false`,
+		},
+		{
+			goType: lsp.SemanticTokensFullSupported,
+			json:   `true`,
+		},
+		{
+			goType: lsp.SemanticTokensFullDelta,
+			json:   `{"delta":true}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.SemanticTokensFull(0)
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensFull_Unmarshal(t *testing.T) {
+	cases := []struct {
+		json string
+		want lsp.SemanticTokensFull
+	}{
+		{
+			json: `{}`,
+			want: lsp.SemanticTokensFullSupported,
+		},
+		{
+			json: `{"delta":false}`,
+			want: lsp.SemanticTokensFullSupported,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.SemanticTokensFull(0)
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensRange_Unmarshal(t *testing.T) {
+	cases := []struct {
+		json string
+		want lsp.SemanticTokensRange
+	}{
+		{
+			json: `{}`,
+			want: true,
+		},
+		{
+			json: `true`,
+			want: true,
+		},
+		{
+			json: `false`,
+			want: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.SemanticTokensRange(false)
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyItem_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.CallHierarchyItem
+		json   string
+	}{
+		{
+			goType: lsp.CallHierarchyItem{
+				Name:           "name",
+				Kind:           lsp.SymbolKindFunction,
+				Tags:           []lsp.SymbolTag{lsp.SymbolTagDeprecated},
+				Detail:         "detail",
+				URI:            "uri",
+				Range:          lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 3}},
+				SelectionRange: lsp.Range{Start: lsp.Position{Line: 1, Character: 5}, End: lsp.Position{Line: 1, Character: 8}},
+				Data:           "data",
+			},
+			json: `{"name":"name","kind":12,"tags":[1],"detail":"detail","uri":"uri","range":{"start":{"line":1,"character":0},"end":{"line":3,"character":0}},"selectionRange":{"start":{"line":1,"character":5},"end":{"line":1,"character":8}},"data":"data"}`,
+		},
+		{
+			goType: lsp.CallHierarchyItem{Name: "name", Kind: lsp.SymbolKindMethod, URI: "uri"},
+			json:   `{"name":"name","kind":6,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.CallHierarchyItem{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyIncomingCall_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.CallHierarchyIncomingCall
+		json   string
+	}{
+		{
+			goType: lsp.CallHierarchyIncomingCall{From: lsp.CallHierarchyItem{Name: "name", Kind: lsp.SymbolKindMethod, URI: "uri"}, FromRanges: []lsp.Range{lsp.Range{Start: lsp.Position{Line: 2, Character: 1}, End: lsp.Position{Line: 2, Character: 4}}}},
+			json:   `{"from":{"name":"name","kind":6,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}},"fromRanges":[{"start":{"line":2,"character":1},"end":{"line":2,"character":4}}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.CallHierarchyIncomingCall{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyOutgoingCall_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.CallHierarchyOutgoingCall
+		json   string
+	}{
+		{
+			goType: lsp.CallHierarchyOutgoingCall{To: lsp.CallHierarchyItem{Name: "name", Kind: lsp.SymbolKindMethod, URI: "uri"}, FromRanges: []lsp.Range{lsp.Range{Start: lsp.Position{Line: 2, Character: 1}, End: lsp.Position{Line: 2, Character: 4}}}},
+			json:   `{"to":{"name":"name","kind":6,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}},"fromRanges":[{"start":{"line":2,"character":1},"end":{"line":2,"character":4}}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.CallHierarchyOutgoingCall{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeHierarchyItem_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.TypeHierarchyItem
+		json   string
+	}{
+		{
+			goType: lsp.TypeHierarchyItem{
+				Name:           "name",
+				Kind:           lsp.SymbolKindClass,
+				Tags:           []lsp.SymbolTag{lsp.SymbolTagDeprecated},
+				Detail:         "detail",
+				URI:            "uri",
+				Range:          lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 3}},
+				SelectionRange: lsp.Range{Start: lsp.Position{Line: 1, Character: 6}, End: lsp.Position{Line: 1, Character: 9}},
+				Data:           "data",
+			},
+			json: `{"name":"name","kind":5,"tags":[1],"detail":"detail","uri":"uri","range":{"start":{"line":1,"character":0},"end":{"line":3,"character":0}},"selectionRange":{"start":{"line":1,"character":6},"end":{"line":1,"character":9}},"data":"data"}`,
+		},
+		{
+			goType: lsp.TypeHierarchyItem{Name: "name", Kind: lsp.SymbolKindInterface, URI: "uri"},
+			json:   `{"name":"name","kind":11,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.TypeHierarchyItem{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlayHint_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.InlayHint
+		json   string
+	}{
+		{
+			goType: lsp.InlayHint{
+				Position: lsp.Position{Line: 1, Character: 2},
+				Label:    lsp.InlayHintLabel{Value: ": int"},
+				Kind:     lsp.InlayHintKindType,
+				TextEdits: []lsp.TextEdit{
+					{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 2}, End: lsp.Position{Line: 1, Character: 2}}, NewText: ": int"},
+				},
+				Tooltip:      &lsp.MarkupUnion{String: strPtr("tooltip")},
+				PaddingLeft:  true,
+				PaddingRight: true,
+				Data:         "data",
+			},
+			json: `{"position":{"line":1,"character":2},"label":": int","kind":1,"textEdits":[{"range":{"start":{"line":1,"character":2},"end":{"line":1,"character":2}},"newText":": int"}],"tooltip":"tooltip","paddingLeft":true,"paddingRight":true,"data":"data"}`,
+		},
+		{
+			goType: lsp.InlayHint{
+				Position: lsp.Position{Line: 1, Character: 2},
+				Label: lsp.InlayHintLabel{
+					Parts: []lsp.InlayHintLabelPart{
+						{
+							Value:    "name",
+							Tooltip:  &lsp.MarkupUnion{MarkupContent: &lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: "**name**"}},
+							Location: &lsp.Location{URI: "uri", Range: lsp.Range{End: lsp.Position{Character: 3}}},
+							Command:  &lsp.Command{Title: "title", Command: "command"},
+						},
+						{Value: ":"},
+					},
+				},
+				Kind: lsp.InlayHintKindParameter,
+			},
+			json: `{"position":{"line":1,"character":2},"label":[{"value":"name","tooltip":{"kind":"markdown","value":"**name**"},"location":{"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":3}}},"command":{"title":"title","command":"command"}},{"value":":"}],"kind":2}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.InlayHint{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlayHintLabel_Marshal(t *testing.T) {
+	v := lsp.InlayHintLabel{
+		Value: "value",
+		Parts: []lsp.InlayHintLabelPart{{Value: "value"}},
+	}
+
+	if _, err := json.Marshal(&v); err == nil {
+		t.Fatalf("should be error but not")
+	}
+}
+
+func TestDocumentDiagnosticReport_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DocumentDiagnosticReport
+		json   string
+	}{
+		{
+			goType: lsp.DocumentDiagnosticReport{
+				Full: &lsp.FullDocumentDiagnosticReport{
+					ResultID: "1",
+					Items:    []lsp.Diagnostic{lsp.Diagnostic{Range: lsp.Range{End: lsp.Position{Character: 1}}, Message: "message"}},
+				},
+				RelatedDocuments: map[lsp.DocumentURI]lsp.RelatedDocumentDiagnosticReport{
+					"related": {Unchanged: &lsp.UnchangedDocumentDiagnosticReport{ResultID: "2"}},
+				},
+			},
+			json: `{"kind":"full","resultId":"1","items":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"message":"message"}],"relatedDocuments":{"related":{"kind":"unchanged","resultId":"2"}}}`,
+		},
+		{
+			goType: lsp.DocumentDiagnosticReport{
+				Full: &lsp.FullDocumentDiagnosticReport{Items: []lsp.Diagnostic{}},
+			},
+			json: `{"kind":"full","items":[]}`,
+		},
+		{
+			goType: lsp.DocumentDiagnosticReport{
+				Unchanged: &lsp.UnchangedDocumentDiagnosticReport{ResultID: "1"},
+			},
+			json: `{"kind":"unchanged","resultId":"1"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.DocumentDiagnosticReport{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentDiagnosticReportPartialResult_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.DocumentDiagnosticReportPartialResult
+		json   string
+	}{
+		{
+			goType: lsp.DocumentDiagnosticReportPartialResult{
+				RelatedDocuments: map[lsp.DocumentURI]*lsp.RelatedDocumentDiagnosticReport{
+					"full":      {Full: &lsp.FullDocumentDiagnosticReport{Items: []lsp.Diagnostic{}}},
+					"unchanged": {Unchanged: &lsp.UnchangedDocumentDiagnosticReport{ResultID: "1"}},
+				},
+			},
+			json: `{"relatedDocuments":{"full":{"kind":"full","items":[]},"unchanged":{"kind":"unchanged","resultId":"1"}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.DocumentDiagnosticReportPartialResult{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentDiagnosticReport_Marshal(t *testing.T) {
+	cases := []struct {
+		input lsp.DocumentDiagnosticReport
+	}{
+		{
+			input: lsp.DocumentDiagnosticReport{},
+		},
+		{
+			input: lsp.DocumentDiagnosticReport{
+				Full:      &lsp.FullDocumentDiagnosticReport{},
+				Unchanged: &lsp.UnchangedDocumentDiagnosticReport{ResultID: "1"},
+			},
+		},
+		{
+			input: lsp.DocumentDiagnosticReport{
+				Unchanged: &lsp.UnchangedDocumentDiagnosticReport{},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			if _, err := json.Marshal(&tt.input); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+
+func TestDocumentDiagnosticReport_Unmarshal(t *testing.T) {
+	cases := []struct {
+		input string
+	}{
+		{
+			input: `{}`,
+		},
+		{
+			input: `{"kind":"full"}`,
+		},
+		{
+			input: `{"kind":"unchanged"}`,
+		},
+		{
+			input: `{"kind":"hoge","resultId":"1"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.DocumentDiagnosticReport{}
+
+			if err := json.Unmarshal([]byte(tt.input), &got); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}
+func TestWorkspaceDiagnosticReport_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.WorkspaceDiagnosticReport
+		json   string
+	}{
+		{
+			goType: lsp.WorkspaceDiagnosticReport{
+				Items: []lsp.WorkspaceDocumentDiagnosticReport{
+					{
+						URI:     "uri1",
+						Version: intPtr(1),
+						Full:    &lsp.FullDocumentDiagnosticReport{ResultID: "1", Items: []lsp.Diagnostic{lsp.Diagnostic{Range: lsp.Range{End: lsp.Position{Character: 1}}, Message: "message"}}},
+					},
+					{
+						URI:       "uri2",
+						Unchanged: &lsp.UnchangedDocumentDiagnosticReport{ResultID: "2"},
+					},
+				},
+			},
+			json: `{"items":[{"kind":"full","resultId":"1","items":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":1}},"message":"message"}],"uri":"uri1","version":1},{"kind":"unchanged","resultId":"2","uri":"uri2","version":null}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.WorkspaceDiagnosticReport{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotebookDocumentFilter_Unmarshal(t *testing.T) {
+	cases := []struct {
+		json string
+		want lsp.NotebookDocumentFilter
+	}{
+		{
+			json: `"jupyter-notebook"`,
+			want: lsp.NotebookDocumentFilter{NotebookType: "jupyter-notebook"},
+		},
+		{
+			json: `{"scheme":"file","pattern":"**/*.ipynb"}`,
+			want: lsp.NotebookDocumentFilter{Scheme: "file", Pattern: "**/*.ipynb"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.NotebookDocumentFilter{}
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileOperationFilter_Match(t *testing.T) {
+	cases := []struct {
+		filter lsp.FileOperationFilter
+		uri    string
+		kind   lsp.FileOperationPatternKind
+		want   bool
+	}{
+		{
+			filter: lsp.FileOperationFilter{Scheme: "file", Pattern: lsp.FileOperationPattern{Glob: "**/*.go"}},
+			uri:    "file:///home/gopher/main.go",
+			want:   true,
+		},
+		{
+			filter: lsp.FileOperationFilter{Scheme: "file", Pattern: lsp.FileOperationPattern{Glob: "**/*.go"}},
+			uri:    "untitled:///main.go",
+			want:   false,
+		},
+		{
+			filter: lsp.FileOperationFilter{Pattern: lsp.FileOperationPattern{Glob: "/src/*.go"}},
+			uri:    "file:///src/pkg/main.go",
+			want:   false,
+		},
+		{
+			filter: lsp.FileOperationFilter{Pattern: lsp.FileOperationPattern{Glob: "**/*.{go,mod}"}},
+			uri:    "file:///src/go.mod",
+			want:   true,
+		},
+		{
+			filter: lsp.FileOperationFilter{Pattern: lsp.FileOperationPattern{Glob: "**/v[0-9]/?.go"}},
+			uri:    "file:///src/v2/a.go",
+			want:   true,
+		},
+		{
+			filter: lsp.FileOperationFilter{Pattern: lsp.FileOperationPattern{Glob: "**/[!a]*.go"}},
+			uri:    "file:///src/a.go",
+			want:   false,
+		},
+		{
+			filter: lsp.FileOperationFilter{
+				Pattern: lsp.FileOperationPattern{
+					Glob:    "**/*.GO",
+					Options: &lsp.FileOperationPatternOptions{IgnoreCase: true},
+				},
+			},
+			uri:  "file:///src/main.go",
+			want: true,
+		},
+		{
+			filter: lsp.FileOperationFilter{
+				Pattern: lsp.FileOperationPattern{Glob: "**", Matches: lsp.FileOperationPatternKindFolder},
+			},
+			uri:  "file:///src/main.go",
+			kind: lsp.FileOperationPatternKindFile,
+			want: false,
+		},
+		{
+			filter: lsp.FileOperationFilter{
+				Pattern: lsp.FileOperationPattern{Glob: "**", Matches: lsp.FileOperationPatternKindFolder},
+			},
+			uri:  "file:///src/pkg",
+			want: true,
+		},
+		{
+			filter: lsp.FileOperationFilter{
+				Pattern: lsp.FileOperationPattern{Glob: "**/pkg", Matches: lsp.FileOperationPatternKindFolder},
+			},
+			uri:  "file:///src/pkg/",
+			kind: lsp.FileOperationPatternKindFolder,
+			want: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			if got := tt.filter.Match(tt.uri, tt.kind); got != tt.want {
+				t.Fatalf("want %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWorkspaceSymbol_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.WorkspaceSymbol
+		json   string
+	}{
+		{
+			goType: lsp.WorkspaceSymbol{
+				Name:     "Server",
+				Kind:     lsp.SymbolKindStruct,
+				Location: lsp.WorkspaceSymbolLocation{URI: lsp.DocumentURI("file:///server.go")},
+				Data:     "data",
+			},
+			json: `{"name":"Server","kind":23,"location":{"uri":"file:///server.go"},"data":"data"}`,
+		},
+		{
+			goType: lsp.WorkspaceSymbol{
+				Name:          "Serve",
+				Kind:          lsp.SymbolKindMethod,
+				Tags:          []lsp.SymbolTag{lsp.SymbolTagDeprecated},
+				ContainerName: "Server",
+				Location: lsp.WorkspaceSymbolLocation{
+					URI: lsp.DocumentURI("file:///server.go"),
+					Range: &lsp.Range{
+						Start: lsp.Position{Line: 1, Character: 0},
+						End:   lsp.Position{Line: 1, Character: 5},
+					},
+				},
+			},
+			json: `{"name":"Serve","kind":6,"tags":[1],"containerName":"Server","location":{"uri":"file:///server.go","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":5}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.WorkspaceSymbol{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLinkedEditingRanges_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.LinkedEditingRanges
+		json   string
+	}{
+		{
+			goType: lsp.LinkedEditingRanges{
+				Ranges: []lsp.Range{
+					{Start: lsp.Position{Line: 0, Character: 1}, End: lsp.Position{Line: 0, Character: 4}},
+					{Start: lsp.Position{Line: 2, Character: 2}, End: lsp.Position{Line: 2, Character: 5}},
+				},
+				WordPattern: "[a-z]+",
+			},
+			json: `{"ranges":[{"start":{"line":0,"character":1},"end":{"line":0,"character":4}},{"start":{"line":2,"character":2},"end":{"line":2,"character":5}}],"wordPattern":"[a-z]+"}`,
+		},
+		{
+			goType: lsp.LinkedEditingRanges{
+				Ranges: []lsp.Range{},
+			},
+			json: `{"ranges":[]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.LinkedEditingRanges{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMoniker_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.Moniker
+		json   string
+	}{
+		{
+			goType: lsp.Moniker{
+				Scheme:     "gomod",
+				Identifier: "github.com/tennashi/lsp:Server",
+				Unique:     lsp.UniquenessLevelScheme,
+				Kind:       lsp.MonikerKindExport,
+			},
+			json: `{"scheme":"gomod","identifier":"github.com/tennashi/lsp:Server","unique":"scheme","kind":"export"}`,
+		},
+		{
+			goType: lsp.Moniker{
+				Scheme:     "local",
+				Identifier: "x",
+				Unique:     lsp.UniquenessLevelDocument,
+			},
+			json: `{"scheme":"local","identifier":"x","unique":"document"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.Moniker{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineValue_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.InlineValue
+		json   string
+	}{
+		{
+			goType: lsp.InlineValue{
+				Text: &lsp.InlineValueText{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}}, Text: "x = 1"},
+			},
+			json: `{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}},"text":"x = 1"}`,
+		},
+		{
+			goType: lsp.InlineValue{
+				VariableLookup: &lsp.InlineValueVariableLookup{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}}, VariableName: "x"},
+			},
+			json: `{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}},"variableName":"x","caseSensitiveLookup":false}`,
+		},
+		{
+			goType: lsp.InlineValue{
+				EvaluatableExpression: &lsp.InlineValueEvaluatableExpression{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}}, Expression: "x + 1"},
+			},
+			json: `{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}},"expression":"x + 1"}`,
+		},
+		{
+			goType: lsp.InlineValue{
+				EvaluatableExpression: &lsp.InlineValueEvaluatableExpression{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}}},
+			},
+			json: `{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.InlineValue{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineCompletionItem_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goType lsp.InlineCompletionItem
+		json   string
+	}{
+		{
+			goType: lsp.InlineCompletionItem{
+				InsertText: lsp.InlineCompletionText{Value: "fmt.Println()"},
+				FilterText: "fmt",
+				Range:      &lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}},
+			},
+			json: `{"insertText":"fmt.Println()","filterText":"fmt","range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}}}`,
+		},
+		{
+			goType: lsp.InlineCompletionItem{
+				InsertText: lsp.InlineCompletionText{
+					Snippet: &lsp.StringValue{Kind: "snippet", Value: "fmt.Println($1)"},
+				},
+			},
+			json: `{"insertText":{"kind":"snippet","value":"fmt.Println($1)"}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoType := lsp.InlineCompletionItem{}
+
+			err = json.Unmarshal(gotJSON, &gotGoType)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goType, gotGoType, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -6,3 +6,11 @@ import (
 )
 
 var cmpOpt = cmp.AllowUnexported(lsp.ProgressToken{}, lsp.IntOrString{})
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func strPtr(v string) *string {
+	return &v
+}
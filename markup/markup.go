@@ -0,0 +1,191 @@
+// Package markup builds the markdown documentation for the hovers and the completion items.
+package markup
+
+import (
+	"strings"
+
+	"github.com/tennashi/lsp"
+)
+
+// Inline is a piece of text in a paragraph or a heading.
+type Inline struct {
+	markdown string
+	plain    string
+}
+
+// Text returns the text which is escaped to be rendered literally.
+func Text(s string) Inline {
+	return Inline{
+		markdown: escape(s),
+		plain:    s,
+	}
+}
+
+func InlineCode(code string) Inline {
+	code = strings.ReplaceAll(code, "\n", " ")
+
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	pad := ""
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		pad = " "
+	}
+
+	return Inline{
+		markdown: fence + pad + code + pad + fence,
+		plain:    code,
+	}
+}
+
+func Link(text, url string) Inline {
+	if text == "" {
+		// the link without the text is invisible in markdown
+		text = url
+	}
+
+	plain := url
+	if text != "" && text != url {
+		plain = text + " (" + url + ")"
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, "<", `\<`, ">", `\>`, "\n", "")
+
+	return Inline{
+		markdown: "[" + escape(text) + "](<" + r.Replace(url) + ">)",
+		plain:    plain,
+	}
+}
+
+// Builder builds a document from the blocks separated by blank lines.
+// The zero value is ready to use.
+type Builder struct {
+	markdown []string
+	plain    []string
+}
+
+func (b *Builder) add(markdown, plain string) *Builder {
+	b.markdown = append(b.markdown, markdown)
+	b.plain = append(b.plain, plain)
+	return b
+}
+
+// Heading adds the heading of the level, which is clamped to 1-6.
+func (b *Builder) Heading(level int, text string) *Builder {
+	switch {
+	case level < 1:
+		level = 1
+	case level > 6:
+		level = 6
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+
+	return b.add(strings.Repeat("#", level)+" "+escape(text), text)
+}
+
+func (b *Builder) Paragraph(inlines ...Inline) *Builder {
+	var md, plain strings.Builder
+	for _, in := range inlines {
+		md.WriteString(in.markdown)
+		plain.WriteString(in.plain)
+	}
+
+	return b.add(strings.TrimLeft(md.String(), " \t"), plain.String())
+}
+
+func (b *Builder) CodeBlock(lang, code string) *Builder {
+	code = strings.TrimSuffix(code, "\n")
+
+	n := longestRun(code, '`') + 1
+	if n < 3 {
+		n = 3
+	}
+	fence := strings.Repeat("`", n)
+	if strings.Contains(lang, "`") {
+		fence = strings.Repeat("~", longestRun(code, '~')+3)
+	}
+
+	return b.add(fence+lang+"\n"+code+"\n"+fence, code)
+}
+
+func (b *Builder) HorizontalRule() *Builder {
+	return b.add("---", "---")
+}
+
+func (b *Builder) Markdown() string {
+	return strings.Join(b.markdown, "\n\n")
+}
+
+func (b *Builder) PlainText() string {
+	return strings.Join(b.plain, "\n\n")
+}
+
+// MarkupContent returns the markdown content unless formats is not empty and does not contain markdown,
+// the plaintext content otherwise.
+func (b *Builder) MarkupContent(formats []lsp.MarkupKind) lsp.MarkupContent {
+	if len(formats) == 0 {
+		return lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: b.Markdown()}
+	}
+
+	for _, f := range formats {
+		if f == lsp.MarkupKindMarkdown {
+			return lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: b.Markdown()}
+		}
+	}
+
+	return lsp.MarkupContent{Kind: lsp.MarkupKindPlainText, Value: b.PlainText()}
+}
+
+// escape escapes s to be rendered literally by a CommonMark renderer.
+// The characters which may start a block are escaped only at the beginning of a line.
+func escape(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		// leading spaces may start an indented code block
+		if i > 0 {
+			l = strings.TrimLeft(l, " \t")
+		}
+
+		var b strings.Builder
+		for j := 0; j < len(l); j++ {
+			c := l[j]
+			switch {
+			case strings.IndexByte("\\`*_[]<>&!|~", c) >= 0:
+				b.WriteByte('\\')
+			case j == 0 && strings.IndexByte("+-=", c) >= 0:
+				b.WriteByte('\\')
+			case c == '#' && (j == 0 || l[j-1] == ' '):
+				b.WriteByte('\\')
+			case (c == '.' || c == ')') && j > 0 && isDigits(l[:j]):
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func longestRun(s string, c byte) int {
+	longest, n := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			n = 0
+			continue
+		}
+		n++
+		if n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
@@ -0,0 +1,103 @@
+package markup_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+	"github.com/tennashi/lsp/markup"
+)
+
+func TestBuilder(t *testing.T) {
+	cases := []struct {
+		build     func(b *markup.Builder)
+		markdown  string
+		plainText string
+	}{
+		{
+			build: func(b *markup.Builder) {
+				b.Heading(2, "func *foo_bar").
+					Paragraph(markup.Text("Returns "), markup.InlineCode("a*b"), markup.Text(" or _nil_.")).
+					CodeBlock("go", "func foo_bar() *T\n").
+					HorizontalRule().
+					Paragraph(markup.Link("docs [v1]", "https://example.com/a b"))
+			},
+			markdown:  "## func \\*foo\\_bar\n\nReturns `a*b` or \\_nil\\_.\n\n```go\nfunc foo_bar() *T\n```\n\n---\n\n[docs \\[v1\\]](<https://example.com/a b>)",
+			plainText: "func *foo_bar\n\nReturns a*b or _nil_.\n\nfunc foo_bar() *T\n\n---\n\ndocs [v1] (https://example.com/a b)",
+		},
+		{
+			build: func(b *markup.Builder) {
+				b.Heading(0, "# C# #").
+					Paragraph(markup.Text("- item\n1. one\n    indented <b>&amp;</b>"))
+			},
+			markdown:  "# \\# C# \\#\n\n\\- item\n1\\. one\nindented \\<b\\>\\&amp;\\</b\\>",
+			plainText: "# C# #\n\n- item\n1. one\n    indented <b>&amp;</b>",
+		},
+		{
+			build: func(b *markup.Builder) {
+				b.Paragraph(markup.InlineCode("`x` ``y``")).
+					CodeBlock("", "```\ncode\n```")
+			},
+			markdown:  "``` `x` ``y`` ```\n\n````\n```\ncode\n```\n````",
+			plainText: "`x` ``y``\n\n```\ncode\n```",
+		},
+		{
+			build: func(b *markup.Builder) {
+				b.Paragraph(markup.Text("See "), markup.Link("", "https://example.com"))
+			},
+			markdown:  "See [https://example.com](<https://example.com>)",
+			plainText: "See https://example.com",
+		},
+		{
+			build:     func(b *markup.Builder) {},
+			markdown:  "",
+			plainText: "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			b := &markup.Builder{}
+			tt.build(b)
+
+			if diff := cmp.Diff(tt.markdown, b.Markdown()); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.plainText, b.PlainText()); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuilder_MarkupContent(t *testing.T) {
+	b := &markup.Builder{}
+	b.Paragraph(markup.InlineCode("x"))
+
+	cases := []struct {
+		formats []lsp.MarkupKind
+		want    lsp.MarkupContent
+	}{
+		{
+			formats: nil,
+			want:    lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: "`x`"},
+		},
+		{
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText, lsp.MarkupKindMarkdown},
+			want:    lsp.MarkupContent{Kind: lsp.MarkupKindMarkdown, Value: "`x`"},
+		},
+		{
+			formats: []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want:    lsp.MarkupContent{Kind: lsp.MarkupKindPlainText, Value: "x"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := b.MarkupContent(tt.formats)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
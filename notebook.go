@@ -0,0 +1,274 @@
+package lsp
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// NotebookStore keeps the notebook documents opened by the client and the content of their cells.
+// The zero value is ready to use.
+type NotebookStore struct {
+	// Cells keeps the cell text documents of all the notebooks.
+	Cells TextDocumentStore
+
+	mu        sync.RWMutex
+	notebooks map[DocumentURI]NotebookDocument
+	// the notebook of each cell text document
+	owners map[DocumentURI]DocumentURI
+}
+
+func (s *NotebookStore) Open(p DidOpenNotebookDocumentParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.notebooks == nil {
+		s.notebooks = map[DocumentURI]NotebookDocument{}
+		s.owners = map[DocumentURI]DocumentURI{}
+	}
+
+	nb := p.NotebookDocument
+	nb.Cells = append([]NotebookCell(nil), nb.Cells...)
+	s.notebooks[nb.URI] = nb
+
+	for _, item := range p.CellTextDocuments {
+		s.Cells.Open(item)
+		s.owners[item.URI] = nb.URI
+	}
+}
+
+// Change applies the change to the notebook and its cells.
+// Nothing is changed if it fails.
+func (s *NotebookStore) Change(p DidChangeNotebookDocumentParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nb, ok := s.notebooks[p.NotebookDocument.URI]
+	if !ok {
+		return errors.New("notebook not opened")
+	}
+
+	nb.Version = p.NotebookDocument.Version
+	if p.Change.Metadata != nil {
+		nb.Metadata = p.Change.Metadata
+	}
+
+	// the cells are stored after the whole change is validated
+	opened := []TextDocumentItem{}
+	closed := map[DocumentURI]bool{}
+	docs := map[DocumentURI]TextDocumentItem{}
+
+	if c := p.Change.Cells; c != nil {
+		if st := c.Structure; st != nil {
+			start, end := st.Array.Start, st.Array.Start+st.Array.DeleteCount
+			if start < 0 || st.Array.DeleteCount < 0 || end > len(nb.Cells) {
+				return errors.New("cell array change out of range")
+			}
+
+			cells := make([]NotebookCell, 0, len(nb.Cells)-st.Array.DeleteCount+len(st.Array.Cells))
+			cells = append(cells, nb.Cells[:start]...)
+			cells = append(cells, st.Array.Cells...)
+			cells = append(cells, nb.Cells[end:]...)
+			nb.Cells = cells
+
+			opened = st.DidOpen
+			for _, item := range st.DidOpen {
+				docs[item.URI] = item
+			}
+			for _, id := range st.DidClose {
+				closed[id.URI] = true
+				delete(docs, id.URI)
+			}
+		}
+
+		if len(c.Data) > 0 {
+			nb.Cells = append([]NotebookCell(nil), nb.Cells...)
+		}
+		for _, data := range c.Data {
+			for i := range nb.Cells {
+				if nb.Cells[i].Document == data.Document {
+					nb.Cells[i] = data
+				}
+			}
+		}
+
+		for _, content := range c.TextContent {
+			uri := content.Document.URI
+			doc, ok := docs[uri]
+			if !ok && !closed[uri] {
+				doc, ok = s.Cells.Get(uri)
+			}
+			if !ok {
+				return errors.New("document not opened")
+			}
+
+			doc, err := applyContentChanges(doc, DidChangeTextDocumentParams{
+				TextDocument:   content.Document,
+				ContentChanges: content.Changes,
+			}, s.Cells.Encoding)
+			if err != nil {
+				return err
+			}
+			docs[uri] = doc
+		}
+	}
+
+	for uri := range closed {
+		s.Cells.Close(uri)
+		delete(s.owners, uri)
+	}
+	for _, doc := range docs {
+		s.Cells.Open(doc)
+	}
+	for _, item := range opened {
+		if !closed[item.URI] {
+			s.owners[item.URI] = nb.URI
+		}
+	}
+	s.notebooks[nb.URI] = nb
+
+	return nil
+}
+
+// Close closes the notebook and all of its cells.
+func (s *NotebookStore) Close(uri DocumentURI) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nb, ok := s.notebooks[uri]
+	if !ok {
+		return
+	}
+
+	for _, cell := range nb.Cells {
+		s.Cells.Close(cell.Document)
+		delete(s.owners, cell.Document)
+	}
+	delete(s.notebooks, uri)
+}
+
+func (s *NotebookStore) Get(uri DocumentURI) (NotebookDocument, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nb, ok := s.notebooks[uri]
+	return nb, ok
+}
+
+// NotebookOf returns the notebook which the cell text document belongs to.
+func (s *NotebookStore) NotebookOf(cell DocumentURI) (NotebookDocument, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uri, ok := s.owners[cell]
+	if !ok {
+		return NotebookDocument{}, false
+	}
+
+	nb, ok := s.notebooks[uri]
+	return nb, ok
+}
+
+// Concatenation returns the virtual document concatenating the code cells of the notebook in order.
+func (s *NotebookStore) Concatenation(uri DocumentURI) (NotebookConcatenation, bool) {
+	nb, ok := s.Get(uri)
+	if !ok {
+		return NotebookConcatenation{}, false
+	}
+
+	c := NotebookConcatenation{
+		URI:     nb.URI,
+		Version: nb.Version,
+	}
+
+	var b strings.Builder
+	line := 0
+	for _, cell := range nb.Cells {
+		if cell.Kind != NotebookCellKindCode {
+			continue
+		}
+
+		doc, ok := s.Cells.Get(cell.Document)
+		if !ok {
+			continue
+		}
+
+		text := doc.Text
+		if !strings.HasSuffix(text, "\n") && !strings.HasSuffix(text, "\r") {
+			text += "\n"
+		}
+		b.WriteString(text)
+
+		n := lineCount(text)
+		c.cells = append(c.cells, concatenatedCell{
+			uri:   cell.Document,
+			start: line,
+			lines: n,
+		})
+		line += n
+	}
+	c.Text = b.String()
+
+	return c, true
+}
+
+// NotebookConcatenation is a virtual text document made of the code cells of a notebook.
+// Each cell starts at the beginning of a line.
+type NotebookConcatenation struct {
+	URI     DocumentURI
+	Version int
+	Text    string
+
+	cells []concatenatedCell
+}
+
+type concatenatedCell struct {
+	uri   DocumentURI
+	start int
+	lines int
+}
+
+// Position converts the position in the cell text document to the one in the concatenation.
+func (c NotebookConcatenation) Position(cell DocumentURI, pos Position) (Position, bool) {
+	for _, cc := range c.cells {
+		if cc.uri != cell {
+			continue
+		}
+		if pos.Line < 0 || pos.Line >= cc.lines {
+			return Position{}, false
+		}
+
+		return Position{Line: cc.start + pos.Line, Character: pos.Character}, true
+	}
+
+	return Position{}, false
+}
+
+// CellPosition converts the position in the concatenation to the cell text document and the position in it.
+func (c NotebookConcatenation) CellPosition(pos Position) (DocumentURI, Position, bool) {
+	for _, cc := range c.cells {
+		if pos.Line >= cc.start && pos.Line < cc.start+cc.lines {
+			return cc.uri, Position{Line: pos.Line - cc.start, Character: pos.Character}, true
+		}
+	}
+
+	return "", Position{}, false
+}
+
+// lineCount returns the number of the line breaks in text.
+func lineCount(text string) int {
+	n := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\n':
+			n++
+		case '\r':
+			n++
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+		}
+	}
+
+	return n
+}
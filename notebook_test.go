@@ -0,0 +1,260 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func openTestNotebook(s *lsp.NotebookStore) {
+	s.Open(lsp.DidOpenNotebookDocumentParams{
+		NotebookDocument: lsp.NotebookDocument{
+			URI:          "nb",
+			NotebookType: "jupyter-notebook",
+			Version:      1,
+			Cells: []lsp.NotebookCell{
+				{Kind: lsp.NotebookCellKindCode, Document: "nb#1"},
+				{Kind: lsp.NotebookCellKindMarkup, Document: "nb#2"},
+				{Kind: lsp.NotebookCellKindCode, Document: "nb#3"},
+			},
+		},
+		CellTextDocuments: []lsp.TextDocumentItem{
+			{URI: "nb#1", LanguageID: "python", Version: 1, Text: "import os\nx = 1"},
+			{URI: "nb#2", LanguageID: "markdown", Version: 1, Text: "# title\n"},
+			{URI: "nb#3", LanguageID: "python", Version: 1, Text: "print(x)\n"},
+		},
+	})
+}
+
+func TestNotebookStore_Change(t *testing.T) {
+	testVersion := 2
+
+	cases := []struct {
+		change lsp.NotebookDocumentChangeEvent
+		cells  []lsp.DocumentURI
+		texts  map[lsp.DocumentURI]string
+		err    bool
+	}{
+		{
+			change: lsp.NotebookDocumentChangeEvent{
+				Cells: &lsp.NotebookDocumentCellChanges{
+					Structure: &lsp.NotebookDocumentCellChangeStructure{
+						Array: lsp.NotebookCellArrayChange{
+							Start:       1,
+							DeleteCount: 1,
+							Cells: []lsp.NotebookCell{
+								{Kind: lsp.NotebookCellKindCode, Document: "nb#4"},
+							},
+						},
+						DidOpen: []lsp.TextDocumentItem{
+							{URI: "nb#4", LanguageID: "python", Version: 1, Text: "y = 2\n"},
+						},
+						DidClose: []lsp.TextDocumentIdentifier{
+							{URI: "nb#2"},
+						},
+					},
+				},
+			},
+			cells: []lsp.DocumentURI{"nb#1", "nb#4", "nb#3"},
+			texts: map[lsp.DocumentURI]string{
+				"nb#1": "import os\nx = 1",
+				"nb#3": "print(x)\n",
+				"nb#4": "y = 2\n",
+			},
+		},
+		{
+			change: lsp.NotebookDocumentChangeEvent{
+				Cells: &lsp.NotebookDocumentCellChanges{
+					TextContent: []lsp.NotebookDocumentCellContentChanges{
+						{
+							Document: lsp.VersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "nb#3"},
+								Version:                &testVersion,
+							},
+							Changes: []lsp.TextDocumentContentChangeEvent{
+								{
+									Range: &lsp.Range{
+										Start: lsp.Position{Line: 0, Character: 6},
+										End:   lsp.Position{Line: 0, Character: 7},
+									},
+									Text: "os",
+								},
+							},
+						},
+					},
+				},
+			},
+			cells: []lsp.DocumentURI{"nb#1", "nb#2", "nb#3"},
+			texts: map[lsp.DocumentURI]string{
+				"nb#1": "import os\nx = 1",
+				"nb#2": "# title\n",
+				"nb#3": "print(os)\n",
+			},
+		},
+		{
+			change: lsp.NotebookDocumentChangeEvent{
+				Cells: &lsp.NotebookDocumentCellChanges{
+					Structure: &lsp.NotebookDocumentCellChangeStructure{
+						Array: lsp.NotebookCellArrayChange{
+							Start:       2,
+							DeleteCount: 2,
+						},
+					},
+				},
+			},
+			cells: []lsp.DocumentURI{"nb#1", "nb#2", "nb#3"},
+			texts: map[lsp.DocumentURI]string{
+				"nb#1": "import os\nx = 1",
+				"nb#2": "# title\n",
+				"nb#3": "print(x)\n",
+			},
+			err: true,
+		},
+		{
+			change: lsp.NotebookDocumentChangeEvent{
+				Cells: &lsp.NotebookDocumentCellChanges{
+					Structure: &lsp.NotebookDocumentCellChangeStructure{
+						Array: lsp.NotebookCellArrayChange{
+							Start:       1,
+							DeleteCount: 1,
+							Cells: []lsp.NotebookCell{
+								{Kind: lsp.NotebookCellKindCode, Document: "nb#4"},
+							},
+						},
+						DidOpen: []lsp.TextDocumentItem{
+							{URI: "nb#4", LanguageID: "python", Version: 1, Text: "y = 2\n"},
+						},
+						DidClose: []lsp.TextDocumentIdentifier{
+							{URI: "nb#2"},
+						},
+					},
+					Data: []lsp.NotebookCell{
+						{Kind: lsp.NotebookCellKindMarkup, Document: "nb#1"},
+					},
+					TextContent: []lsp.NotebookDocumentCellContentChanges{
+						{
+							Document: lsp.VersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "nb#1"},
+								Version:                &testVersion,
+							},
+							Changes: []lsp.TextDocumentContentChangeEvent{{Text: "x = 2"}},
+						},
+						{
+							Document: lsp.VersionedTextDocumentIdentifier{
+								TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "nb#3"},
+								Version:                &testVersion,
+							},
+							Changes: []lsp.TextDocumentContentChangeEvent{
+								{Range: &lsp.Range{Start: lsp.Position{Line: 5}, End: lsp.Position{Line: 5}}, Text: "x"},
+							},
+						},
+					},
+				},
+			},
+			cells: []lsp.DocumentURI{"nb#1", "nb#2", "nb#3"},
+			texts: map[lsp.DocumentURI]string{
+				"nb#1": "import os\nx = 1",
+				"nb#2": "# title\n",
+				"nb#3": "print(x)\n",
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.NotebookStore{}
+			openTestNotebook(s)
+			before, _ := s.Get("nb")
+
+			err := s.Change(lsp.DidChangeNotebookDocumentParams{
+				NotebookDocument: lsp.VersionedNotebookDocumentIdentifier{URI: "nb", Version: 2},
+				Change:           tt.change,
+			})
+			nb, ok := s.Get("nb")
+			if !ok {
+				t.Fatalf("notebook should be opened")
+			}
+
+			if tt.err {
+				if err == nil {
+					t.Fatalf("should be error but not")
+				}
+				// the failed change should not be applied partially
+				if diff := cmp.Diff(before, nb, cmpOpt); diff != "" {
+					t.Fatalf("mismatch (-want +got):\n%s", diff)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+				if nb.Version != 2 {
+					t.Fatalf("version should be updated but: %d", nb.Version)
+				}
+			}
+
+			cells := []lsp.DocumentURI{}
+			for _, cell := range nb.Cells {
+				cells = append(cells, cell.Document)
+			}
+			if diff := cmp.Diff(tt.cells, cells); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			for _, uri := range []lsp.DocumentURI{"nb#1", "nb#2", "nb#3", "nb#4"} {
+				doc, ok := s.Cells.Get(uri)
+				want, opened := tt.texts[uri]
+				if ok != opened {
+					t.Fatalf("%s: opened should be %v", uri, opened)
+				}
+				if doc.Text != want {
+					t.Fatalf("%s: want %q but got %q", uri, want, doc.Text)
+				}
+
+				_, ok = s.NotebookOf(uri)
+				if ok != opened {
+					t.Fatalf("%s: owned should be %v", uri, opened)
+				}
+			}
+		})
+	}
+}
+
+func TestNotebookStore_Concatenation(t *testing.T) {
+	s := &lsp.NotebookStore{}
+	openTestNotebook(s)
+
+	c, ok := s.Concatenation("nb")
+	if !ok {
+		t.Fatalf("notebook should be opened")
+	}
+	if diff := cmp.Diff("import os\nx = 1\nprint(x)\n", c.Text); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	uri, pos, ok := c.CellPosition(lsp.Position{Line: 2, Character: 6})
+	if !ok {
+		t.Fatalf("position should be in a cell")
+	}
+	if uri != "nb#3" || pos != (lsp.Position{Line: 0, Character: 6}) {
+		t.Fatalf("unexpected cell position: %s %+v", uri, pos)
+	}
+
+	pos, ok = c.Position("nb#1", lsp.Position{Line: 1, Character: 4})
+	if !ok {
+		t.Fatalf("position should be in the concatenation")
+	}
+	if pos != (lsp.Position{Line: 1, Character: 4}) {
+		t.Fatalf("unexpected position: %+v", pos)
+	}
+
+	if _, ok := c.Position("nb#2", lsp.Position{}); ok {
+		t.Fatalf("markup cell should not be in the concatenation")
+	}
+
+	s.Close("nb")
+	if _, ok := s.Cells.Get("nb#1"); ok {
+		t.Fatalf("cell should be closed with the notebook")
+	}
+}
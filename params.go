@@ -19,11 +19,29 @@ type CancelParams struct {
 	ID jsonrpc2.ID `json:"id,omitempty"`
 }
 
+type SetTraceParams struct {
+	Value TraceConfig `json:"value"`
+}
+
+type LogTraceParams struct {
+	Message string `json:"message"`
+	Verbose string `json:"verbose,omitempty"`
+}
+
+type WorkDoneProgressCancelParams struct {
+	Token ProgressToken `json:"token"`
+}
+
 type ProgressParams struct {
 	Token ProgressToken `json:"token"`
 	Value interface{}   `json:"value"`
 }
 
+type ApplyWorkspaceEditParams struct {
+	Label string        `json:"label,omitempty"`
+	Edit  WorkspaceEdit `json:"edit"`
+}
+
 type DidChangeWorkspaceFoldersParams struct {
 	Event WorkspaceFoldersChangeEvent `json:"event"`
 }
@@ -213,3 +231,140 @@ type SelectionRangeParams struct {
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
 	Positions    []Position             `json:"positions"`
 }
+
+type SemanticTokensParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type SemanticTokensDeltaParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocument     TextDocumentIdentifier `json:"textDocument"`
+	PreviousResultID string                 `json:"previousResultId"`
+}
+
+type SemanticTokensRangeParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type CallHierarchyPrepareParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+}
+
+type CallHierarchyIncomingCallsParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	Item CallHierarchyItem `json:"item"`
+}
+
+type CallHierarchyOutgoingCallsParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	Item CallHierarchyItem `json:"item"`
+}
+
+type TypeHierarchyPrepareParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+}
+
+type TypeHierarchySupertypesParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	Item TypeHierarchyItem `json:"item"`
+}
+
+type TypeHierarchySubtypesParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	Item TypeHierarchyItem `json:"item"`
+}
+
+type InlayHintParams struct {
+	WorkDoneProgressParams
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type DocumentDiagnosticParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	TextDocument     TextDocumentIdentifier `json:"textDocument"`
+	Identifier       string                 `json:"identifier,omitempty"`
+	PreviousResultID string                 `json:"previousResultId,omitempty"`
+}
+
+type WorkspaceDiagnosticParams struct {
+	WorkDoneProgressParams
+	PartialResultParams
+	Identifier        string             `json:"identifier,omitempty"`
+	PreviousResultIDs []PreviousResultID `json:"previousResultIds"`
+}
+
+type DidOpenNotebookDocumentParams struct {
+	NotebookDocument  NotebookDocument   `json:"notebookDocument"`
+	CellTextDocuments []TextDocumentItem `json:"cellTextDocuments"`
+}
+
+type DidChangeNotebookDocumentParams struct {
+	NotebookDocument VersionedNotebookDocumentIdentifier `json:"notebookDocument"`
+	Change           NotebookDocumentChangeEvent         `json:"change"`
+}
+
+type DidSaveNotebookDocumentParams struct {
+	NotebookDocument NotebookDocumentIdentifier `json:"notebookDocument"`
+}
+
+type DidCloseNotebookDocumentParams struct {
+	NotebookDocument  NotebookDocumentIdentifier `json:"notebookDocument"`
+	CellTextDocuments []TextDocumentIdentifier   `json:"cellTextDocuments"`
+}
+
+type CreateFilesParams struct {
+	Files []FileCreate `json:"files"`
+}
+
+type RenameFilesParams struct {
+	Files []FileRename `json:"files"`
+}
+
+type DeleteFilesParams struct {
+	Files []FileDelete `json:"files"`
+}
+
+type ShowDocumentParams struct {
+	URI       DocumentURI `json:"uri"`
+	External  bool        `json:"external,omitempty"`
+	TakeFocus bool        `json:"takeFocus,omitempty"`
+	Selection *Range      `json:"selection,omitempty"`
+}
+
+type LinkedEditingRangeParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+}
+
+type MonikerParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+	PartialResultParams
+}
+
+type InlineValueParams struct {
+	WorkDoneProgressParams
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      InlineValueContext     `json:"context"`
+}
+
+type InlineCompletionParams struct {
+	TextDocumentPositionParams
+	WorkDoneProgressParams
+	Context InlineCompletionContext `json:"context"`
+}
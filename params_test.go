@@ -2556,3 +2556,742 @@ func TestSelectionRangeParams_MarshalUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyWorkspaceEditParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.ApplyWorkspaceEditParams
+		json     string
+	}{
+		{
+			goStruct: lsp.ApplyWorkspaceEditParams{
+				Label: "label",
+				Edit: lsp.WorkspaceEdit{
+					Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+						"uri": {{NewText: "text"}},
+					},
+				},
+			},
+			json: `{"label":"label","edit":{"changes":{"uri":[{"range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"newText":"text"}]}}}`,
+		},
+		{
+			goStruct: lsp.ApplyWorkspaceEditParams{},
+			json:     `{"edit":{}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.ApplyWorkspaceEditParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensDeltaParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.SemanticTokensDeltaParams
+		json     string
+	}{
+		{
+			goStruct: lsp.SemanticTokensDeltaParams{
+				TextDocument:     lsp.TextDocumentIdentifier{URI: "uri"},
+				PreviousResultID: "1",
+			},
+			json: `{"textDocument":{"uri":"uri"},"previousResultId":"1"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.SemanticTokensDeltaParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensRangeParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.SemanticTokensRangeParams
+		json     string
+	}{
+		{
+			goStruct: lsp.SemanticTokensRangeParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: "uri"},
+				Range:        lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 2}},
+			},
+			json: `{"textDocument":{"uri":"uri"},"range":{"start":{"line":1,"character":0},"end":{"line":2,"character":0}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.SemanticTokensRangeParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyPrepareParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.CallHierarchyPrepareParams
+		json     string
+	}{
+		{
+			goStruct: lsp.CallHierarchyPrepareParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: "uri"},
+					Position:     lsp.Position{Line: 1, Character: 2},
+				},
+			},
+			json: `{"textDocument":{"uri":"uri"},"position":{"line":1,"character":2}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.CallHierarchyPrepareParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyIncomingCallsParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.CallHierarchyIncomingCallsParams
+		json     string
+	}{
+		{
+			goStruct: lsp.CallHierarchyIncomingCallsParams{Item: lsp.CallHierarchyItem{Name: "name", Kind: lsp.SymbolKindMethod, URI: "uri"}},
+			json:     `{"item":{"name":"name","kind":6,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.CallHierarchyIncomingCallsParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCallHierarchyOutgoingCallsParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.CallHierarchyOutgoingCallsParams
+		json     string
+	}{
+		{
+			goStruct: lsp.CallHierarchyOutgoingCallsParams{Item: lsp.CallHierarchyItem{Name: "name", Kind: lsp.SymbolKindMethod, URI: "uri"}},
+			json:     `{"item":{"name":"name","kind":6,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.CallHierarchyOutgoingCallsParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeHierarchyPrepareParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.TypeHierarchyPrepareParams
+		json     string
+	}{
+		{
+			goStruct: lsp.TypeHierarchyPrepareParams{
+				TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: "uri"},
+					Position:     lsp.Position{Line: 1, Character: 2},
+				},
+			},
+			json: `{"textDocument":{"uri":"uri"},"position":{"line":1,"character":2}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.TypeHierarchyPrepareParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeHierarchySupertypesParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.TypeHierarchySupertypesParams
+		json     string
+	}{
+		{
+			goStruct: lsp.TypeHierarchySupertypesParams{Item: lsp.TypeHierarchyItem{Name: "name", Kind: lsp.SymbolKindInterface, URI: "uri"}},
+			json:     `{"item":{"name":"name","kind":11,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.TypeHierarchySupertypesParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeHierarchySubtypesParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.TypeHierarchySubtypesParams
+		json     string
+	}{
+		{
+			goStruct: lsp.TypeHierarchySubtypesParams{Item: lsp.TypeHierarchyItem{Name: "name", Kind: lsp.SymbolKindInterface, URI: "uri"}},
+			json:     `{"item":{"name":"name","kind":11,"uri":"uri","range":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}},"selectionRange":{"start":{"line":0,"character":0},"end":{"line":0,"character":0}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.TypeHierarchySubtypesParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlayHintParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.InlayHintParams
+		json     string
+	}{
+		{
+			goStruct: lsp.InlayHintParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: "uri"},
+				Range:        lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 2}},
+			},
+			json: `{"textDocument":{"uri":"uri"},"range":{"start":{"line":1,"character":0},"end":{"line":2,"character":0}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.InlayHintParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentDiagnosticParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.DocumentDiagnosticParams
+		json     string
+	}{
+		{
+			goStruct: lsp.DocumentDiagnosticParams{
+				TextDocument:     lsp.TextDocumentIdentifier{URI: "uri"},
+				Identifier:       "identifier",
+				PreviousResultID: "1",
+			},
+			json: `{"textDocument":{"uri":"uri"},"identifier":"identifier","previousResultId":"1"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.DocumentDiagnosticParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceDiagnosticParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.WorkspaceDiagnosticParams
+		json     string
+	}{
+		{
+			goStruct: lsp.WorkspaceDiagnosticParams{
+				PartialResultParams: lsp.PartialResultParams{
+					PartialResultToken: &testStrToken,
+				},
+				PreviousResultIDs: []lsp.PreviousResultID{{URI: "uri", Value: "1"}},
+			},
+			json: `{"partialResultToken":"token","previousResultIds":[{"uri":"uri","value":"1"}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.WorkspaceDiagnosticParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDidOpenNotebookDocumentParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.DidOpenNotebookDocumentParams
+		json     string
+	}{
+		{
+			goStruct: lsp.DidOpenNotebookDocumentParams{
+				NotebookDocument: lsp.NotebookDocument{
+					URI:          lsp.DocumentURI("nb"),
+					NotebookType: "jupyter-notebook",
+					Version:      1,
+					Cells: []lsp.NotebookCell{
+						{
+							Kind:             lsp.NotebookCellKindCode,
+							Document:         lsp.DocumentURI("nb#1"),
+							ExecutionSummary: &lsp.ExecutionSummary{ExecutionOrder: 1},
+						},
+					},
+				},
+				CellTextDocuments: []lsp.TextDocumentItem{
+					{URI: lsp.DocumentURI("nb#1"), LanguageID: "python", Version: 1, Text: "x = 1"},
+				},
+			},
+			json: `{"notebookDocument":{"uri":"nb","notebookType":"jupyter-notebook","version":1,"cells":[{"kind":2,"document":"nb#1","executionSummary":{"executionOrder":1}}]},"cellTextDocuments":[{"uri":"nb#1","languageId":"python","version":1,"text":"x = 1"}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.DidOpenNotebookDocumentParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDidChangeNotebookDocumentParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.DidChangeNotebookDocumentParams
+		json     string
+	}{
+		{
+			goStruct: lsp.DidChangeNotebookDocumentParams{
+				NotebookDocument: lsp.VersionedNotebookDocumentIdentifier{Version: 2, URI: lsp.DocumentURI("nb")},
+				Change: lsp.NotebookDocumentChangeEvent{
+					Cells: &lsp.NotebookDocumentCellChanges{
+						Structure: &lsp.NotebookDocumentCellChangeStructure{
+							Array: lsp.NotebookCellArrayChange{Start: 0, DeleteCount: 1},
+							DidClose: []lsp.TextDocumentIdentifier{
+								{URI: lsp.DocumentURI("nb#1")},
+							},
+						},
+					},
+				},
+			},
+			json: `{"notebookDocument":{"version":2,"uri":"nb"},"change":{"cells":{"structure":{"array":{"start":0,"deleteCount":1},"didClose":[{"uri":"nb#1"}]}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.DidChangeNotebookDocumentParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenameFilesParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.RenameFilesParams
+		json     string
+	}{
+		{
+			goStruct: lsp.RenameFilesParams{
+				Files: []lsp.FileRename{
+					{OldURI: "file:///src/a.go", NewURI: "file:///src/b.go"},
+				},
+			},
+			json: `{"files":[{"oldUri":"file:///src/a.go","newUri":"file:///src/b.go"}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.RenameFilesParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShowDocumentParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.ShowDocumentParams
+		json     string
+	}{
+		{
+			goStruct: lsp.ShowDocumentParams{
+				URI:       lsp.DocumentURI("file:///src/main.go"),
+				TakeFocus: true,
+				Selection: &lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 0},
+					End:   lsp.Position{Line: 1, Character: 4},
+				},
+			},
+			json: `{"uri":"file:///src/main.go","takeFocus":true,"selection":{"start":{"line":1,"character":0},"end":{"line":1,"character":4}}}`,
+		},
+		{
+			goStruct: lsp.ShowDocumentParams{
+				URI:      lsp.DocumentURI("https://pkg.go.dev"),
+				External: true,
+			},
+			json: `{"uri":"https://pkg.go.dev","external":true}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.ShowDocumentParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetTraceParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.SetTraceParams
+		json     string
+	}{
+		{
+			goStruct: lsp.SetTraceParams{Value: lsp.TraceConfigVerbose},
+			json:     `{"value":"verbose"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.SetTraceParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLogTraceParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.LogTraceParams
+		json     string
+	}{
+		{
+			goStruct: lsp.LogTraceParams{Message: "message", Verbose: "verbose"},
+			json:     `{"message":"message","verbose":"verbose"}`,
+		},
+		{
+			goStruct: lsp.LogTraceParams{Message: "message"},
+			json:     `{"message":"message"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.LogTraceParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineValueParams_MarshalUnmarshal(t *testing.T) {
+	cases := []struct {
+		goStruct lsp.InlineValueParams
+		json     string
+	}{
+		{
+			goStruct: lsp.InlineValueParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: lsp.DocumentURI("file:///main.go")},
+				Range:        lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}},
+				Context:      lsp.InlineValueContext{FrameID: 1, StoppedLocation: lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 3}}},
+			},
+			json: `{"textDocument":{"uri":"file:///main.go"},"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}},"context":{"frameId":1,"stoppedLocation":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}}}}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			gotJSON, err := json.Marshal(&tt.goStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.json, string(gotJSON), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			gotGoStruct := lsp.InlineValueParams{}
+
+			err = json.Unmarshal(gotJSON, &gotGoStruct)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.goStruct, gotGoStruct, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
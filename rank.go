@@ -0,0 +1,124 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tennashi/lsp/fuzzy"
+)
+
+// RankCompletionItems returns the items matching word, typically the word before the cursor, ordered by the fuzzy score.
+// The ties are ordered by SortText then Label.
+// FilterText is set to Label if empty, and SortText is overwritten to keep the order on the clients which sort the items locally.
+func RankCompletionItems(items []CompletionItem, word string) []CompletionItem {
+	type ranked struct {
+		item  CompletionItem
+		score int
+	}
+
+	rs := []ranked{}
+	for _, item := range items {
+		if item.FilterText == "" {
+			item.FilterText = item.Label
+		}
+
+		m, ok := fuzzy.Match(word, item.FilterText)
+		if !ok {
+			continue
+		}
+		rs = append(rs, ranked{item: item, score: m.Score})
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.item.SortText != b.item.SortText {
+			return a.item.SortText < b.item.SortText
+		}
+		return a.item.Label < b.item.Label
+	})
+
+	width := len(strconv.Itoa(len(rs)))
+	res := make([]CompletionItem, 0, len(rs))
+	for i, r := range rs {
+		r.item.SortText = fmt.Sprintf("%0*d", width, i)
+		res = append(res, r.item)
+	}
+
+	return res
+}
+
+// RankWorkspaceSymbols returns the symbols matching query ordered by the fuzzy score.
+// The ties are ordered by Name then ContainerName.
+func RankWorkspaceSymbols(symbols []SymbolInformation, query string) []SymbolInformation {
+	names := make([]symbolName, 0, len(symbols))
+	for _, sym := range symbols {
+		names = append(names, symbolName{name: sym.Name, container: sym.ContainerName})
+	}
+
+	res := []SymbolInformation{}
+	for _, i := range rankSymbolNames(names, query) {
+		res = append(res, symbols[i])
+	}
+
+	return res
+}
+
+// RankWorkspaceSymbolItems is RankWorkspaceSymbols for the WorkspaceSymbol results of OnWorkspaceSymbols.
+func RankWorkspaceSymbolItems(symbols []WorkspaceSymbol, query string) []WorkspaceSymbol {
+	names := make([]symbolName, 0, len(symbols))
+	for _, sym := range symbols {
+		names = append(names, symbolName{name: sym.Name, container: sym.ContainerName})
+	}
+
+	res := []WorkspaceSymbol{}
+	for _, i := range rankSymbolNames(names, query) {
+		res = append(res, symbols[i])
+	}
+
+	return res
+}
+
+type symbolName struct {
+	name      string
+	container string
+}
+
+// rankSymbolNames returns the indexes of names matching query ordered by the fuzzy score.
+func rankSymbolNames(names []symbolName, query string) []int {
+	type ranked struct {
+		index int
+		score int
+	}
+
+	rs := []ranked{}
+	for i, n := range names {
+		m, ok := fuzzy.Match(query, n.name)
+		if !ok {
+			continue
+		}
+		rs = append(rs, ranked{index: i, score: m.Score})
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		na, nb := names[a.index], names[b.index]
+		if na.name != nb.name {
+			return na.name < nb.name
+		}
+		return na.container < nb.container
+	})
+
+	res := make([]int, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, r.index)
+	}
+
+	return res
+}
@@ -0,0 +1,141 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func TestRankCompletionItems(t *testing.T) {
+	cases := []struct {
+		items []lsp.CompletionItem
+		word  string
+		want  []lsp.CompletionItem
+	}{
+		{
+			items: []lsp.CompletionItem{
+				{Label: "xfooBar"},
+				{Label: "baz"},
+				{Label: "fooBar"},
+				{Label: "label", FilterText: "foo_bar"},
+			},
+			word: "fb",
+			want: []lsp.CompletionItem{
+				{Label: "label", FilterText: "foo_bar", SortText: "0"},
+				{Label: "fooBar", FilterText: "fooBar", SortText: "1"},
+				{Label: "xfooBar", FilterText: "xfooBar", SortText: "2"},
+			},
+		},
+		{
+			items: []lsp.CompletionItem{
+				{Label: "c", SortText: "a"},
+				{Label: "b"},
+				{Label: "a"},
+				{Label: "d"},
+				{Label: "e"},
+				{Label: "f"},
+				{Label: "g"},
+				{Label: "h"},
+				{Label: "i"},
+				{Label: "j"},
+			},
+			word: "",
+			want: []lsp.CompletionItem{
+				{Label: "a", FilterText: "a", SortText: "00"},
+				{Label: "b", FilterText: "b", SortText: "01"},
+				{Label: "d", FilterText: "d", SortText: "02"},
+				{Label: "e", FilterText: "e", SortText: "03"},
+				{Label: "f", FilterText: "f", SortText: "04"},
+				{Label: "g", FilterText: "g", SortText: "05"},
+				{Label: "h", FilterText: "h", SortText: "06"},
+				{Label: "i", FilterText: "i", SortText: "07"},
+				{Label: "j", FilterText: "j", SortText: "08"},
+				{Label: "c", FilterText: "c", SortText: "09"},
+			},
+		},
+		{
+			items: nil,
+			word:  "a",
+			want:  []lsp.CompletionItem{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.RankCompletionItems(tt.items, tt.word)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRankWorkspaceSymbols(t *testing.T) {
+	cases := []struct {
+		symbols []lsp.SymbolInformation
+		query   string
+		want    []lsp.SymbolInformation
+	}{
+		{
+			symbols: []lsp.SymbolInformation{
+				{Name: "NewServer", ContainerName: "b"},
+				{Name: "Server"},
+				{Name: "Conn"},
+				{Name: "NewServer", ContainerName: "a"},
+			},
+			query: "serv",
+			want: []lsp.SymbolInformation{
+				{Name: "Server"},
+				{Name: "NewServer", ContainerName: "a"},
+				{Name: "NewServer", ContainerName: "b"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.RankWorkspaceSymbols(tt.symbols, tt.query)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRankWorkspaceSymbolItems(t *testing.T) {
+	cases := []struct {
+		symbols []lsp.WorkspaceSymbol
+		query   string
+		want    []lsp.WorkspaceSymbol
+	}{
+		{
+			symbols: []lsp.WorkspaceSymbol{
+				{Name: "NewServer", ContainerName: "b"},
+				{Name: "Server"},
+				{Name: "Conn"},
+				{Name: "NewServer", ContainerName: "a"},
+			},
+			query: "serv",
+			want: []lsp.WorkspaceSymbol{
+				{Name: "Server"},
+				{Name: "NewServer", ContainerName: "a"},
+				{Name: "NewServer", ContainerName: "b"},
+			},
+		},
+		{
+			symbols: nil,
+			query:   "a",
+			want:    []lsp.WorkspaceSymbol{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.RankWorkspaceSymbolItems(tt.symbols, tt.query)
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SemanticTokensBuilder encodes the tokens at absolute positions into the relative integers of SemanticTokens.
+type SemanticTokensBuilder struct {
+	types     map[SemanticTokenType]int
+	modifiers map[SemanticTokenModifier]int
+	tokens    []semanticToken
+}
+
+type semanticToken struct {
+	line, char, length int
+	typ, modifiers     int
+}
+
+func NewSemanticTokensBuilder(legend SemanticTokensLegend) *SemanticTokensBuilder {
+	b := &SemanticTokensBuilder{
+		types:     map[SemanticTokenType]int{},
+		modifiers: map[SemanticTokenModifier]int{},
+	}
+	for i, t := range legend.TokenTypes {
+		b.types[t] = i
+	}
+	for i, m := range legend.TokenModifiers {
+		b.modifiers[m] = i
+	}
+
+	return b
+}
+
+// Push adds the token at line and char, which must be in the legend.
+// The tokens may be pushed in any order.
+func (b *SemanticTokensBuilder) Push(line, char, length int, typ SemanticTokenType, modifiers ...SemanticTokenModifier) error {
+	if line < 0 || char < 0 || length <= 0 {
+		return fmt.Errorf("invalid token: line %d, char %d, length %d", line, char, length)
+	}
+
+	t, ok := b.types[typ]
+	if !ok {
+		return fmt.Errorf("token type %q is not in the legend", typ)
+	}
+
+	mods := 0
+	for _, m := range modifiers {
+		i, ok := b.modifiers[m]
+		if !ok {
+			return fmt.Errorf("token modifier %q is not in the legend", m)
+		}
+		mods |= 1 << uint(i)
+	}
+
+	b.tokens = append(b.tokens, semanticToken{
+		line:      line,
+		char:      char,
+		length:    length,
+		typ:       t,
+		modifiers: mods,
+	})
+
+	return nil
+}
+
+// Build returns the tokens sorted by their positions in the relative format.
+func (b *SemanticTokensBuilder) Build() []int {
+	tokens := append([]semanticToken(nil), b.tokens...)
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].line != tokens[j].line {
+			return tokens[i].line < tokens[j].line
+		}
+		return tokens[i].char < tokens[j].char
+	})
+
+	data := make([]int, 0, 5*len(tokens))
+	line, char := 0, 0
+	for _, t := range tokens {
+		deltaChar := t.char
+		if t.line == line {
+			deltaChar = t.char - char
+		}
+		data = append(data, t.line-line, deltaChar, t.length, t.typ, t.modifiers)
+		line, char = t.line, t.char
+	}
+
+	return data
+}
+
+// DiffSemanticTokens returns the edits which turn old into new.
+func DiffSemanticTokens(old, new []int) []SemanticTokensEdit {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix && old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	if prefix == len(old) && prefix == len(new) {
+		return []SemanticTokensEdit{}
+	}
+
+	return []SemanticTokensEdit{
+		{
+			Start:       prefix,
+			DeleteCount: len(old) - prefix - suffix,
+			Data:        append([]int(nil), new[prefix:len(new)-suffix]...),
+		},
+	}
+}
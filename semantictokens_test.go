@@ -0,0 +1,103 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp"
+)
+
+func TestSemanticTokensBuilder(t *testing.T) {
+	legend := lsp.SemanticTokensLegend{
+		TokenTypes: []lsp.SemanticTokenType{
+			lsp.SemanticTokenTypeKeyword,
+			lsp.SemanticTokenTypeFunction,
+			lsp.SemanticTokenTypeVariable,
+		},
+		TokenModifiers: []lsp.SemanticTokenModifier{
+			lsp.SemanticTokenModifierDeclaration,
+			lsp.SemanticTokenModifierReadonly,
+		},
+	}
+
+	b := lsp.NewSemanticTokensBuilder(legend)
+	pushes := []struct {
+		line, char, length int
+		typ                lsp.SemanticTokenType
+		modifiers          []lsp.SemanticTokenModifier
+	}{
+		{2, 5, 3, lsp.SemanticTokenTypeVariable, []lsp.SemanticTokenModifier{lsp.SemanticTokenModifierDeclaration, lsp.SemanticTokenModifierReadonly}},
+		{0, 0, 4, lsp.SemanticTokenTypeKeyword, nil},
+		{0, 5, 3, lsp.SemanticTokenTypeFunction, []lsp.SemanticTokenModifier{lsp.SemanticTokenModifierDeclaration}},
+		{2, 1, 3, lsp.SemanticTokenTypeKeyword, nil},
+	}
+	for _, p := range pushes {
+		if err := b.Push(p.line, p.char, p.length, p.typ, p.modifiers...); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+	}
+
+	want := []int{
+		0, 0, 4, 0, 0,
+		0, 5, 3, 1, 1,
+		2, 1, 3, 0, 0,
+		0, 4, 3, 2, 3,
+	}
+	if diff := cmp.Diff(want, b.Build()); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := b.Push(0, 0, 1, lsp.SemanticTokenTypeClass); err == nil {
+		t.Fatalf("should be error but not")
+	}
+	if err := b.Push(0, 0, 1, lsp.SemanticTokenTypeKeyword, lsp.SemanticTokenModifierStatic); err == nil {
+		t.Fatalf("should be error but not")
+	}
+	if err := b.Push(0, 0, 0, lsp.SemanticTokenTypeKeyword); err == nil {
+		t.Fatalf("should be error but not")
+	}
+}
+
+func TestDiffSemanticTokens(t *testing.T) {
+	cases := []struct {
+		old  []int
+		new  []int
+		want []lsp.SemanticTokensEdit
+	}{
+		{
+			old:  []int{0, 0, 4, 0, 0, 0, 5, 3, 1, 1},
+			new:  []int{0, 0, 4, 0, 0, 0, 5, 3, 1, 1},
+			want: []lsp.SemanticTokensEdit{},
+		},
+		{
+			old: []int{0, 0, 4, 0, 0, 0, 5, 3, 1, 1},
+			new: []int{0, 0, 4, 0, 0, 1, 2, 3, 0, 0, 0, 5, 3, 1, 1},
+			want: []lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []int{1, 2, 3, 0, 0}},
+			},
+		},
+		{
+			old: []int{0, 0, 4, 0, 0, 0, 5, 3, 1, 1},
+			new: []int{0, 0, 4, 0, 0},
+			want: []lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 5},
+			},
+		},
+		{
+			old: nil,
+			new: []int{0, 0, 4, 0, 0},
+			want: []lsp.SemanticTokensEdit{
+				{Start: 0, DeleteCount: 0, Data: []int{0, 0, 4, 0, 0}},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got := lsp.DiffSemanticTokens(tt.old, tt.new)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
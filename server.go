@@ -1,13 +1,20 @@
 package lsp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
@@ -53,15 +60,46 @@ const (
 )
 
 type Server struct {
+	// accessed atomically, the first field to be 64-bit aligned
+	semanticTokensID uint64
+
 	state     int32 // serverState
 	exitCh    chan int
 	cancelCh  chan jsonrpc2.ID
 	cancelFns *sync.Map
 
+	trace atomic.Value // TraceConfig
+
+	positionEncoding atomic.Value // PositionEncoding
+
+	// the types of CompletionItem.Data by their names
+	dataTypes sync.Map
+
+	// the last SemanticTokens of each document, to compute the deltas from
+	semanticTokens sync.Map
+
+	clientCapabilities ClientCapabilities
+
+	// the compiled filters of the file operations by their methods, set at initialize
+	fileOperationFilters map[string]fileOperationFilters
+
 	Info         ServerInfo
 	Capabilities ServerCapabilities
 
+	// LogTraceMessages, if true, reports the messages received and the responses sent by $/logTrace.
+	LogTraceMessages bool
+
+	// Documents, if set, is kept in sync with the text documents opened by the client.
+	Documents *TextDocumentStore
+
+	// Notebooks, if set, is kept in sync with the notebook documents opened by the client.
+	Notebooks *NotebookStore
+
+	// CompletionCache, if set, is invalidated when the cached words are changed otherwise than by typing.
+	CompletionCache *CompletionCache
+
 	OnProgress                      func(context.Context, *Conn, ProgressParams) error
+	OnWorkDoneProgressCancel        func(context.Context, *Conn, WorkDoneProgressCancelParams) error
 	OnInitialize                    func(context.Context, *Conn, InitializeParams) (InitializeResult, error)
 	OnInitialized                   func(context.Context, *Conn) error
 	OnShutdown                      func(context.Context, *Conn) error
@@ -69,6 +107,8 @@ type Server struct {
 	OnDidChangeConfiguration        func(context.Context, *Conn, DidChangeConfigurationParams) error
 	OnDidChangeWatchedFiles         func(context.Context, *Conn, DidChangeWatchedFilesParams) error
 	OnWorkspaceSymbol               func(context.Context, *Conn, WorkspaceSymbolParams) ([]SymbolInformation, error)
+	OnWorkspaceSymbols              func(context.Context, *Conn, WorkspaceSymbolParams) ([]WorkspaceSymbol, error) // called instead of OnWorkspaceSymbol if set
+	OnWorkspaceSymbolResolve        func(context.Context, *Conn, WorkspaceSymbol) (WorkspaceSymbol, error)
 	OnExecuteCommand                func(context.Context, *Conn, ExecuteCommandParams) (interface{}, error)
 	OnDidOpenTextDocument           func(context.Context, *Conn, DidOpenTextDocumentParams) error
 	OnDidChangeTextDocument         func(context.Context, *Conn, DidChangeTextDocumentParams) error
@@ -76,18 +116,29 @@ type Server struct {
 	OnWillSaveWaitUntilTextDocument func(context.Context, *Conn, WillSaveTextDocumentParams) ([]TextEdit, error)
 	OnDidSaveTextDocument           func(context.Context, *Conn, DidSaveTextDocumentParams) error
 	OnDidCloseTextDocument          func(context.Context, *Conn, DidCloseTextDocumentParams) error
+	OnDidOpenNotebookDocument       func(context.Context, *Conn, DidOpenNotebookDocumentParams) error
+	OnDidChangeNotebookDocument     func(context.Context, *Conn, DidChangeNotebookDocumentParams) error
+	OnDidSaveNotebookDocument       func(context.Context, *Conn, DidSaveNotebookDocumentParams) error
+	OnDidCloseNotebookDocument      func(context.Context, *Conn, DidCloseNotebookDocumentParams) error
+	OnWillCreateFiles               func(context.Context, *Conn, CreateFilesParams) (*WorkspaceEdit, error)
+	OnDidCreateFiles                func(context.Context, *Conn, CreateFilesParams) error
+	OnWillRenameFiles               func(context.Context, *Conn, RenameFilesParams) (*WorkspaceEdit, error)
+	OnDidRenameFiles                func(context.Context, *Conn, RenameFilesParams) error
+	OnWillDeleteFiles               func(context.Context, *Conn, DeleteFilesParams) (*WorkspaceEdit, error)
+	OnDidDeleteFiles                func(context.Context, *Conn, DeleteFilesParams) error
 	OnCompletion                    func(context.Context, *Conn, CompletionParams) (CompletionList, error)
 	OnCompletionItemResolve         func(context.Context, *Conn, CompletionItem) (CompletionItem, error)
 	OnHover                         func(context.Context, *Conn, HoverParams) (*Hover, error)
 	OnSignatureHelp                 func(context.Context, *Conn, SignatureHelpParams) (*SignatureHelp, error)
-	OnDeclaration                   func(context.Context, *Conn, DeclarationParams) ([]interface{}, error)
-	OnDefinition                    func(context.Context, *Conn, DefinitionParams) ([]interface{}, error)
-	OnTypeDefinition                func(context.Context, *Conn, TypeDefinitionParams) ([]interface{}, error)
-	OnImplementation                func(context.Context, *Conn, ImplementationParams) ([]interface{}, error)
+	OnDeclaration                   func(context.Context, *Conn, DeclarationParams) (*DefinitionResult, error)
+	OnDefinition                    func(context.Context, *Conn, DefinitionParams) (*DefinitionResult, error)
+	OnTypeDefinition                func(context.Context, *Conn, TypeDefinitionParams) (*DefinitionResult, error)
+	OnImplementation                func(context.Context, *Conn, ImplementationParams) (*DefinitionResult, error)
 	OnReferences                    func(context.Context, *Conn, ReferenceParams) ([]Location, error)
 	OnDocumentHighlight             func(context.Context, *Conn, DocumentHighlightParams) ([]DocumentHighlight, error)
-	OnDocumentSymbol                func(context.Context, *Conn, DocumentSymbolParams) ([]interface{}, error)
-	OnCodeAction                    func(context.Context, *Conn, CodeActionParams) ([]interface{}, error)
+	OnDocumentSymbol                func(context.Context, *Conn, DocumentSymbolParams) ([]DocumentSymbol, error)
+	OnCodeAction                    func(context.Context, *Conn, CodeActionParams) (CodeActionResult, error)
+	OnCodeActionResolve             func(context.Context, *Conn, CodeAction) (CodeAction, error)
 	OnCodeLens                      func(context.Context, *Conn, CodeLensParams) ([]CodeLens, error)
 	OnCodeLensResolve               func(context.Context, *Conn, CodeLens) (CodeLens, error)
 	OnDocumentLink                  func(context.Context, *Conn, DocumentLinkParams) ([]DocumentLink, error)
@@ -101,6 +152,22 @@ type Server struct {
 	OnPrepareRename                 func(context.Context, *Conn, TextDocumentPositionParams) (interface{}, error)
 	OnFoldingRange                  func(context.Context, *Conn, FoldingRangeParams) ([]FoldingRange, error)
 	OnSelectionRange                func(context.Context, *Conn, SelectionRangeParams) ([]SelectionRange, error)
+	OnSemanticTokensFull            func(context.Context, *Conn, SemanticTokensParams) (*SemanticTokens, error)
+	OnSemanticTokensRange           func(context.Context, *Conn, SemanticTokensRangeParams) (*SemanticTokens, error)
+	OnPrepareCallHierarchy          func(context.Context, *Conn, CallHierarchyPrepareParams) ([]CallHierarchyItem, error)
+	OnIncomingCalls                 func(context.Context, *Conn, CallHierarchyIncomingCallsParams) ([]CallHierarchyIncomingCall, error)
+	OnOutgoingCalls                 func(context.Context, *Conn, CallHierarchyOutgoingCallsParams) ([]CallHierarchyOutgoingCall, error)
+	OnPrepareTypeHierarchy          func(context.Context, *Conn, TypeHierarchyPrepareParams) ([]TypeHierarchyItem, error)
+	OnSupertypes                    func(context.Context, *Conn, TypeHierarchySupertypesParams) ([]TypeHierarchyItem, error)
+	OnSubtypes                      func(context.Context, *Conn, TypeHierarchySubtypesParams) ([]TypeHierarchyItem, error)
+	OnInlayHint                     func(context.Context, *Conn, InlayHintParams) ([]InlayHint, error)
+	OnInlayHintResolve              func(context.Context, *Conn, InlayHint) (InlayHint, error)
+	OnDocumentDiagnostic            func(context.Context, *Conn, DocumentDiagnosticParams) (*DocumentDiagnosticReport, error)
+	OnWorkspaceDiagnostic           func(context.Context, *Conn, WorkspaceDiagnosticParams) (*WorkspaceDiagnosticReport, error)
+	OnLinkedEditingRange            func(context.Context, *Conn, LinkedEditingRangeParams) (*LinkedEditingRanges, error)
+	OnMoniker                       func(context.Context, *Conn, MonikerParams) ([]Moniker, error)
+	OnInlineValue                   func(context.Context, *Conn, InlineValueParams) ([]InlineValue, error)
+	OnInlineCompletion              func(context.Context, *Conn, InlineCompletionParams) (InlineCompletionList, error)
 }
 
 func (s *Server) setState(state serverState) error {
@@ -116,6 +183,34 @@ func (s *Server) getState() serverState {
 
 }
 
+func (s *Server) textDocumentClientCapabilities() TextDocumentClientCapabilities {
+	if s.clientCapabilities.TextDocument == nil {
+		return TextDocumentClientCapabilities{}
+	}
+	return *s.clientCapabilities.TextDocument
+}
+
+// adaptCompletionItem converts the documentation and the snippets of item to those the client supports.
+func (s *Server) adaptCompletionItem(item CompletionItem) CompletionItem {
+	caps := s.textDocumentClientCapabilities().Completion
+	if caps == nil || caps.CompletionItem == nil {
+		return item.withSnippetSupport(false)
+	}
+
+	item = item.withDocumentationFormat(caps.CompletionItem.DocumentationFormat)
+	return item.withSnippetSupport(caps.CompletionItem.SnippetSupport)
+}
+
+// completionResolveProperties returns the properties of the completion items the client can resolve lazily.
+func (s *Server) completionResolveProperties() []string {
+	caps := s.textDocumentClientCapabilities().Completion
+	if caps == nil || caps.CompletionItem == nil || caps.CompletionItem.ResolveSupport == nil {
+		// only these can be resolved lazily before resolveSupport is introduced
+		return []string{"documentation", "detail"}
+	}
+	return caps.CompletionItem.ResolveSupport.Properties
+}
+
 func (s *Server) checkState() error {
 	st := s.getState()
 	if st == serverStateShutdowned {
@@ -138,17 +233,25 @@ func (s *Server) checkState() error {
 }
 
 func (s *Server) Serve(ctx context.Context) error {
+	return s.ServeStream(ctx, jsonrpc2.NewBufferedStream(stdrwc{}, jsonrpc2.VSCodeObjectCodec{}))
+}
+
+// ServeStream serves the client connected through stream instead of the standard input and output.
+func (s *Server) ServeStream(ctx context.Context, stream jsonrpc2.ObjectStream) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	if s.exitCh == nil {
+		s.exitCh = make(chan int, 1)
+	}
+	if s.cancelCh == nil {
+		s.cancelCh = make(chan jsonrpc2.ID)
+	}
+
 	go s.cancelLoop(ctx)
 
-	c := jsonrpc2.NewConn(
-		ctx,
-		jsonrpc2.NewBufferedStream(stdrwc{}, jsonrpc2.VSCodeObjectCodec{}),
-		jsonrpc2.HandlerWithError(s.handle),
-	)
+	c := jsonrpc2.NewConn(ctx, stream, jsonrpc2.HandlerWithError(s.handle))
 	defer c.Close()
 
 	select {
@@ -209,13 +312,17 @@ func (s *Server) cancelLoop(ctx context.Context) {
 			}
 			cancelFn()
 		case <-ctx.Done():
-			break
+			return
 		}
 	}
 }
 
 func (s *Server) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
-	c := wrap(conn)
+	c := wrap(conn, s)
+
+	if s.LogTraceMessages {
+		return s.traceMessage(ctx, c, req)
+	}
 
 	switch req.Notif {
 	case true:
@@ -225,12 +332,56 @@ func (s *Server) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.
 	}
 }
 
+// traceMessage handles req reporting it and its response by $/logTrace.
+// The $/logTrace errors are ignored not to fail the messages.
+func (s *Server) traceMessage(ctx context.Context, c *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	params := ""
+	if req.Params != nil {
+		params = "Params: " + string(*req.Params)
+	}
+
+	if req.Notif {
+		c.LogTrace(ctx, fmt.Sprintf("Received notification '%s'.", req.Method), params)
+		return s.handleNotification(ctx, c, req)
+	}
+
+	c.LogTrace(ctx, fmt.Sprintf("Received request '%s - (%s)'.", req.Method, req.ID), params)
+
+	start := time.Now()
+	res, err := s.handleRequest(ctx, c, req)
+	took := time.Since(start).Milliseconds()
+
+	msg := fmt.Sprintf("Sending response '%s - (%s)'. Processing request took %dms", req.Method, req.ID, took)
+	verbose := ""
+	if err != nil {
+		verbose = "Error: " + err.Error()
+	} else if b, merr := json.Marshal(res); merr == nil {
+		verbose = "Result: " + string(b)
+	}
+	c.LogTrace(ctx, msg, verbose)
+
+	return res, err
+}
+
+func (s *Server) traceConfig() TraceConfig {
+	trace, ok := s.trace.Load().(TraceConfig)
+	if !ok || trace == "" {
+		return TraceConfigOff
+	}
+
+	return trace
+}
+
 func (s *Server) handleNotification(ctx context.Context, c *Conn, req *jsonrpc2.Request) (interface{}, error) {
 	switch req.Method {
 	case "$/cancelRequest":
 		return s.cancelRequest(ctx, c, req)
 	case "$/progress":
 		return s.progress(ctx, c, req)
+	case "$/setTrace":
+		return s.setTrace(ctx, c, req)
+	case "window/workDoneProgress/cancel":
+		return s.workDoneProgressCancel(ctx, c, req)
 	case "initialized":
 		return s.initialized(ctx, c, req)
 	case "exit":
@@ -241,6 +392,12 @@ func (s *Server) handleNotification(ctx context.Context, c *Conn, req *jsonrpc2.
 		return s.didChangeConfiguration(ctx, c, req)
 	case "workspace/didChangeWatchedFiles":
 		return s.didChangeWatchedFiles(ctx, c, req)
+	case "workspace/didCreateFiles":
+		return s.didCreateFiles(ctx, c, req)
+	case "workspace/didRenameFiles":
+		return s.didRenameFiles(ctx, c, req)
+	case "workspace/didDeleteFiles":
+		return s.didDeleteFiles(ctx, c, req)
 	case "textDocument/didOpen":
 		return s.didOpenTextDocument(ctx, c, req)
 	case "textDocument/didChange":
@@ -251,6 +408,14 @@ func (s *Server) handleNotification(ctx context.Context, c *Conn, req *jsonrpc2.
 		return s.didSaveTextDocument(ctx, c, req)
 	case "textDocument/didClose":
 		return s.didCloseTextDocument(ctx, c, req)
+	case "notebookDocument/didOpen":
+		return s.didOpenNotebookDocument(ctx, c, req)
+	case "notebookDocument/didChange":
+		return s.didChangeNotebookDocument(ctx, c, req)
+	case "notebookDocument/didSave":
+		return s.didSaveNotebookDocument(ctx, c, req)
+	case "notebookDocument/didClose":
+		return s.didCloseNotebookDocument(ctx, c, req)
 	default:
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound}
 	}
@@ -276,6 +441,55 @@ func (s *Server) cancelRequest(ctx context.Context, c *Conn, req *jsonrpc2.Reque
 	return nil, nil
 }
 
+// workDoneProgressCancel passes the token to OnWorkDoneProgressCancel.
+// As the messages are handled one by one, the notification never arrives while the request with the token is handled,
+// so only the tokens of the progress the server reports by itself are cancelled.
+func (s *Server) workDoneProgressCancel(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := WorkDoneProgressCancelParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if s.OnWorkDoneProgressCancel == nil {
+		return nil, nil
+	}
+
+	if err := s.OnWorkDoneProgressCancel(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) setTrace(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := SetTraceParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	s.trace.Store(p.Value)
+
+	return nil, nil
+}
+
 func (s *Server) progress(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
 	if err := s.checkState(); err != nil {
 		// the notification should ignore the state error
@@ -415,10 +629,6 @@ func (s *Server) didOpenTextDocument(ctx context.Context, conn *Conn, req *jsonr
 		return nil, nil
 	}
 
-	if s.OnDidOpenTextDocument == nil {
-		return nil, nil
-	}
-
 	if req.Params == nil {
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
 	}
@@ -428,6 +638,14 @@ func (s *Server) didOpenTextDocument(ctx context.Context, conn *Conn, req *jsonr
 		return nil, err
 	}
 
+	if s.Documents != nil {
+		s.Documents.Open(p.TextDocument)
+	}
+
+	if s.OnDidOpenTextDocument == nil {
+		return nil, nil
+	}
+
 	if err := s.OnDidOpenTextDocument(ctx, conn, p); err != nil {
 		return nil, err
 	}
@@ -441,10 +659,6 @@ func (s *Server) didChangeTextDocument(ctx context.Context, conn *Conn, req *jso
 		return nil, nil
 	}
 
-	if s.OnDidChangeTextDocument == nil {
-		return nil, nil
-	}
-
 	if req.Params == nil {
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
 	}
@@ -454,6 +668,23 @@ func (s *Server) didChangeTextDocument(ctx context.Context, conn *Conn, req *jso
 		return nil, err
 	}
 
+	if s.CompletionCache != nil {
+		s.CompletionCache.Change(p)
+	}
+
+	if s.Documents != nil {
+		if err := s.Documents.Change(p); err != nil {
+			// the error of the notification is not sent to the client, so it is shown to the user
+			// to reopen the document, and the handler is not called with the change not stored
+			msg := fmt.Sprintf("failed to change %s, reopen it to synchronize: %v", p.TextDocument.URI, err)
+			return nil, conn.ShowMessage(ctx, MessageTypeError, msg)
+		}
+	}
+
+	if s.OnDidChangeTextDocument == nil {
+		return nil, nil
+	}
+
 	if err := s.OnDidChangeTextDocument(ctx, conn, p); err != nil {
 		return nil, err
 	}
@@ -519,26 +750,184 @@ func (s *Server) didCloseTextDocument(ctx context.Context, conn *Conn, req *json
 		return nil, nil
 	}
 
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := DidCloseTextDocumentParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if s.Documents != nil {
+		s.Documents.Close(p.TextDocument.URI)
+	}
+
+	if s.CompletionCache != nil {
+		s.CompletionCache.Invalidate(p.TextDocument.URI)
+	}
+
+	s.semanticTokens.Delete(p.TextDocument.URI)
+
 	if s.OnDidCloseTextDocument == nil {
 		return nil, nil
 	}
 
+	if err := s.OnDidCloseTextDocument(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) didOpenNotebookDocument(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
 	if req.Params == nil {
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
 	}
 
-	p := DidCloseTextDocumentParams{}
+	p := DidOpenNotebookDocumentParams{}
 	if err := json.Unmarshal(*req.Params, &p); err != nil {
 		return nil, err
 	}
 
-	if err := s.OnDidCloseTextDocument(ctx, conn, p); err != nil {
+	if s.Notebooks != nil {
+		s.Notebooks.Open(p)
+	}
+
+	if s.OnDidOpenNotebookDocument == nil {
+		return nil, nil
+	}
+
+	if err := s.OnDidOpenNotebookDocument(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) didChangeNotebookDocument(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := DidChangeNotebookDocumentParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if s.Notebooks != nil {
+		if err := s.Notebooks.Change(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if cells := p.Change.Cells; cells != nil {
+		if cells.Structure != nil {
+			for _, id := range cells.Structure.DidClose {
+				s.closeCell(id.URI)
+			}
+		}
+
+		if s.CompletionCache != nil {
+			for _, content := range cells.TextContent {
+				s.CompletionCache.Change(DidChangeTextDocumentParams{
+					TextDocument:   content.Document,
+					ContentChanges: content.Changes,
+				})
+			}
+		}
+	}
+
+	if s.OnDidChangeNotebookDocument == nil {
+		return nil, nil
+	}
+
+	if err := s.OnDidChangeNotebookDocument(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) didSaveNotebookDocument(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if s.OnDidSaveNotebookDocument == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := DidSaveNotebookDocumentParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := s.OnDidSaveNotebookDocument(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) didCloseNotebookDocument(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := DidCloseNotebookDocumentParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if s.Notebooks != nil {
+		s.Notebooks.Close(p.NotebookDocument.URI)
+	}
+
+	for _, id := range p.CellTextDocuments {
+		s.closeCell(id.URI)
+	}
+
+	if s.OnDidCloseNotebookDocument == nil {
+		return nil, nil
+	}
+
+	if err := s.OnDidCloseNotebookDocument(ctx, conn, p); err != nil {
 		return nil, err
 	}
 
 	return nil, nil
 }
 
+// closeCell drops the states kept for the cell text document.
+func (s *Server) closeCell(uri DocumentURI) {
+	if s.CompletionCache != nil {
+		s.CompletionCache.Invalidate(uri)
+	}
+
+	s.semanticTokens.Delete(uri)
+}
+
 func (s *Server) handleRequest(ctx context.Context, c *Conn, req *jsonrpc2.Request) (interface{}, error) {
 	if strings.HasPrefix(req.Method, "$/") {
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound}
@@ -554,15 +943,23 @@ func (s *Server) handleRequest(ctx context.Context, c *Conn, req *jsonrpc2.Reque
 		return s.shutdown(ctx, c, req)
 	case "workspace/symbol":
 		return s.workspaceSymbol(ctx, c, req)
+	case "workspaceSymbol/resolve":
+		return s.workspaceSymbolResolve(ctx, c, req)
 	case "workspace/executeCommand":
 		return s.executeCommand(ctx, c, req)
+	case "workspace/willCreateFiles":
+		return s.willCreateFiles(ctx, c, req)
+	case "workspace/willRenameFiles":
+		return s.willRenameFiles(ctx, c, req)
+	case "workspace/willDeleteFiles":
+		return s.willDeleteFiles(ctx, c, req)
 	case "textDocument/willSaveWaitUntil":
 		return s.willSaveWaitUntilTextDocument(ctx, c, req)
 	case "textDocument/completion":
 		return s.completion(ctx, c, req)
 	case "completionItem/resolve":
 		return s.completionItemResolve(ctx, c, req)
-	case "document/hover":
+	case "textDocument/hover":
 		return s.hover(ctx, c, req)
 	case "textDocument/signatureHelp":
 		return s.signatureHelp(ctx, c, req)
@@ -580,6 +977,8 @@ func (s *Server) handleRequest(ctx context.Context, c *Conn, req *jsonrpc2.Reque
 		return s.documentSymbol(ctx, c, req)
 	case "textDocument/codeAction":
 		return s.codeAction(ctx, c, req)
+	case "codeAction/resolve":
+		return s.codeActionResolve(ctx, c, req)
 	case "textDocument/codeLens":
 		return s.codeLens(ctx, c, req)
 	case "codeLens/resolve":
@@ -606,6 +1005,40 @@ func (s *Server) handleRequest(ctx context.Context, c *Conn, req *jsonrpc2.Reque
 		return s.foldingRange(ctx, c, req)
 	case "textDocument/selectionRange":
 		return s.selectionRange(ctx, c, req)
+	case "textDocument/linkedEditingRange":
+		return s.linkedEditingRange(ctx, c, req)
+	case "textDocument/moniker":
+		return s.moniker(ctx, c, req)
+	case "textDocument/inlineValue":
+		return s.inlineValue(ctx, c, req)
+	case "textDocument/inlineCompletion":
+		return s.inlineCompletion(ctx, c, req)
+	case "textDocument/semanticTokens/full":
+		return s.semanticTokensFull(ctx, c, req)
+	case "textDocument/semanticTokens/full/delta":
+		return s.semanticTokensFullDelta(ctx, c, req)
+	case "textDocument/semanticTokens/range":
+		return s.semanticTokensRange(ctx, c, req)
+	case "textDocument/prepareCallHierarchy":
+		return s.prepareCallHierarchy(ctx, c, req)
+	case "callHierarchy/incomingCalls":
+		return s.incomingCalls(ctx, c, req)
+	case "callHierarchy/outgoingCalls":
+		return s.outgoingCalls(ctx, c, req)
+	case "textDocument/prepareTypeHierarchy":
+		return s.prepareTypeHierarchy(ctx, c, req)
+	case "typeHierarchy/supertypes":
+		return s.supertypes(ctx, c, req)
+	case "typeHierarchy/subtypes":
+		return s.subtypes(ctx, c, req)
+	case "textDocument/inlayHint":
+		return s.inlayHint(ctx, c, req)
+	case "inlayHint/resolve":
+		return s.inlayHintResolve(ctx, c, req)
+	case "textDocument/diagnostic":
+		return s.documentDiagnostic(ctx, c, req)
+	case "workspace/diagnostic":
+		return s.workspaceDiagnostic(ctx, c, req)
 	default:
 		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound}
 	}
@@ -633,17 +1066,101 @@ func (s *Server) initialize(ctx context.Context, conn *Conn, req *jsonrpc2.Reque
 	if err := json.Unmarshal(*req.Params, &p); err != nil {
 		return nil, err
 	}
+	s.clientCapabilities = p.Capabilities
+	s.trace.Store(p.Trace)
 
 	res, err := s.OnInitialize(ctx, conn, p)
 	if err != nil {
 		return nil, err
 	}
 
+	s.completeCapabilities(&res.Capabilities)
+	s.compileFileOperationFilters(res.Capabilities)
+
 	s.setState(serverStateInitialized)
 
 	return res, nil
 }
 
+// completeCapabilities adds the capabilities which the server provides on behalf of the handlers.
+func (s *Server) completeCapabilities(caps *ServerCapabilities) {
+	caps.PositionEncoding = s.negotiatePositionEncoding(caps.PositionEncoding)
+	s.positionEncoding.Store(caps.PositionEncoding)
+	if s.Documents != nil {
+		s.Documents.Encoding = caps.PositionEncoding
+	}
+	if s.Notebooks != nil {
+		s.Notebooks.Cells.Encoding = caps.PositionEncoding
+	}
+	if s.CompletionCache != nil {
+		s.CompletionCache.Encoding = caps.PositionEncoding
+	}
+
+	if caps.CompletionProvider != nil && s.OnCompletionItemResolve != nil {
+		opts := *caps.CompletionProvider
+		opts.ResolveProvider = true
+		caps.CompletionProvider = &opts
+	}
+
+	if caps.CodeActionProvider != nil && s.OnCodeActionResolve != nil {
+		opts := *caps.CodeActionProvider
+		opts.ResolveProvider = true
+		caps.CodeActionProvider = &opts
+	}
+
+	if caps.WorkspaceSymbolProvder && caps.WorkspaceSymbolProvider == nil {
+		// the deprecated capability is not understood by the clients
+		caps.WorkspaceSymbolProvider = &WorkspaceSymbolOptions{}
+	}
+
+	if caps.WorkspaceSymbolProvider != nil && s.OnWorkspaceSymbolResolve != nil {
+		opts := *caps.WorkspaceSymbolProvider
+		opts.ResolveProvider = true
+		caps.WorkspaceSymbolProvider = &opts
+	}
+
+	if caps.InlayHintProvider != nil && s.OnInlayHintResolve != nil {
+		opts := *caps.InlayHintProvider
+		opts.ResolveProvider = true
+		caps.InlayHintProvider = &opts
+	}
+
+	codeAction := s.textDocumentClientCapabilities().CodeAction
+	if caps.CodeActionProvider != nil && (codeAction == nil || codeAction.CodeActionLiteralSupport == nil) {
+		opts := ExecuteCommandOptions{}
+		if caps.ExecuteCommandProvider != nil {
+			opts = *caps.ExecuteCommandProvider
+		}
+		opts.Commands = append(append([]string{}, opts.Commands...), CodeActionApplyEditCommand)
+		caps.ExecuteCommandProvider = &opts
+	}
+}
+
+// negotiatePositionEncoding returns the position encoding used in the session.
+// The preferred encoding is used if the client offers it, otherwise the first one the client offers and the server supports.
+// It falls back to UTF-16, which all the clients support.
+func (s *Server) negotiatePositionEncoding(preferred PositionEncoding) PositionEncoding {
+	var offered []PositionEncoding
+	if s.clientCapabilities.General != nil {
+		offered = s.clientCapabilities.General.PositionEncodings
+	}
+
+	for _, enc := range offered {
+		if enc == preferred {
+			return enc
+		}
+	}
+
+	for _, enc := range offered {
+		switch enc {
+		case PositionEncodingUTF8, PositionEncodingUTF16, PositionEncodingUTF32:
+			return enc
+		}
+	}
+
+	return PositionEncodingUTF16
+}
+
 func (s *Server) defaultOnInitialize(context.Context, *Conn, InitializeParams) (InitializeResult, error) {
 	return InitializeResult{
 		ServerInfo:   &s.Info,
@@ -683,7 +1200,7 @@ func (s *Server) workspaceSymbol(ctx context.Context, conn *Conn, req *jsonrpc2.
 		return err, nil
 	}
 
-	if s.OnWorkspaceSymbol == nil {
+	if s.OnWorkspaceSymbol == nil && s.OnWorkspaceSymbols == nil {
 		return nil, nil
 	}
 
@@ -696,20 +1213,60 @@ func (s *Server) workspaceSymbol(ctx context.Context, conn *Conn, req *jsonrpc2.
 		return nil, err
 	}
 
-	res, err := s.OnWorkspaceSymbol(ctx, conn, p)
+	if s.OnWorkspaceSymbols == nil {
+		res, err := s.OnWorkspaceSymbol(ctx, conn, p)
+		if err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+
+	res, err := s.OnWorkspaceSymbols(ctx, conn, p)
 	if err != nil {
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	// the range of the location can be resolved later only if the client supports
+	lazy := false
+	if caps := s.clientCapabilities.Workspace; caps != nil && caps.Symbol != nil && caps.Symbol.ResolveSupport != nil {
+		lazy = containsString(caps.Symbol.ResolveSupport.Properties, "location.range")
+	}
+
+	symbols := make([]WorkspaceSymbol, 0, len(res))
+	for _, sym := range res {
+		deferred := sym.Location.Range == nil
+		switch {
+		case s.OnWorkspaceSymbolResolve == nil:
+			// the symbols without the range cannot be resolved by anyone
+			if deferred {
+				continue
+			}
+		case lazy:
+			if sym.Data, err = s.encodeData(sym.Data, deferred); err != nil {
+				return nil, err
+			}
+		case deferred:
+			if sym, err = s.OnWorkspaceSymbolResolve(ctx, conn, sym); err != nil {
+				return nil, err
+			}
+		}
+		symbols = append(symbols, sym)
+	}
+
+	return symbols, nil
 }
 
-func (s *Server) executeCommand(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+func (s *Server) workspaceSymbolResolve(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
 	if err := s.checkState(); err != nil {
 		return err, nil
 	}
 
-	if s.OnExecuteCommand == nil {
+	if s.OnWorkspaceSymbolResolve == nil {
 		return nil, nil
 	}
 
@@ -717,11 +1274,55 @@ func (s *Server) executeCommand(ctx context.Context, conn *Conn, req *jsonrpc2.R
 		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
 	}
 
+	p := WorkspaceSymbol{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	data, deferred, ok := s.decodeData(p.Data)
+	if ok && !deferred {
+		return p, nil
+	}
+	if ok {
+		p.Data = data
+	}
+
+	res, err := s.OnWorkspaceSymbolResolve(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		if res.Data, err = s.encodeData(res.Data, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) executeCommand(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
 	p := ExecuteCommandParams{}
 	if err := json.Unmarshal(*req.Params, &p); err != nil {
 		return nil, err
 	}
 
+	if p.Command == CodeActionApplyEditCommand {
+		return s.applyCodeActionEdit(ctx, conn, p)
+	}
+
+	if s.OnExecuteCommand == nil {
+		return nil, nil
+	}
+
 	res, err := s.OnExecuteCommand(ctx, conn, p)
 	if err != nil {
 		return nil, err
@@ -730,6 +1331,68 @@ func (s *Server) executeCommand(ctx context.Context, conn *Conn, req *jsonrpc2.R
 	return res, nil
 }
 
+// applyCodeActionEdit executes the command converted from a code action by CodeActionResult.Filter.
+func (s *Server) applyCodeActionEdit(ctx context.Context, conn *Conn, p ExecuteCommandParams) (interface{}, error) {
+	if len(p.Arguments) == 0 {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "missing edit", nil)
+	}
+
+	edit := WorkspaceEdit{}
+	if err := convertArgument(p.Arguments[0], &edit); err != nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, err.Error(), nil)
+	}
+
+	var cmd *Command
+	if len(p.Arguments) >= 2 {
+		cmd = &Command{}
+		if err := convertArgument(p.Arguments[1], cmd); err != nil {
+			return nil, createError(jsonrpc2.CodeInvalidParams, err.Error(), nil)
+		}
+	}
+
+	// the messages are read by the goroutine calling the handlers,
+	// so the response of workspace/applyEdit can be received only after this request is replied
+	go s.applyEdit(context.Background(), conn, edit, cmd, p.WorkDoneProgressParams)
+
+	return nil, nil
+}
+
+// applyEdit applies edit through workspace/applyEdit, then executes cmd if the edit is applied.
+// The failures are reported by window/showMessage.
+func (s *Server) applyEdit(ctx context.Context, conn *Conn, edit WorkspaceEdit, cmd *Command, wp WorkDoneProgressParams) {
+	res, err := conn.ApplyEdit(ctx, "", edit)
+	if err != nil {
+		conn.ShowMessage(ctx, MessageTypeError, "failed to apply the edit: "+err.Error())
+		return
+	}
+	if !res.Applied {
+		conn.ShowMessage(ctx, MessageTypeError, "edit not applied: "+res.FailureReason)
+		return
+	}
+
+	if cmd == nil || s.OnExecuteCommand == nil {
+		return
+	}
+
+	if _, err := s.OnExecuteCommand(ctx, conn, ExecuteCommandParams{
+		WorkDoneProgressParams: wp,
+		Command:                cmd.Command,
+		Arguments:              cmd.Arguments,
+	}); err != nil {
+		conn.ShowMessage(ctx, MessageTypeError, err.Error())
+	}
+}
+
+// convertArgument decodes arg, which is decoded from JSON as interface{}, into v.
+func convertArgument(arg interface{}, v interface{}) error {
+	d, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(d, v)
+}
+
 func (s *Server) willSaveWaitUntilTextDocument(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
 	if err := s.checkState(); err != nil {
 		return err, nil
@@ -779,6 +1442,18 @@ func (s *Server) completion(ctx context.Context, conn *Conn, req *jsonrpc2.Reque
 		return nil, err
 	}
 
+	props := s.completionResolveProperties()
+	items := make([]CompletionItem, 0, len(res.Items))
+	for _, item := range res.Items {
+		if s.OnCompletionItemResolve != nil {
+			if item.Data, err = s.encodeData(item.Data, item.deferred(props)); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, s.adaptCompletionItem(item))
+	}
+	res.Items = items
+
 	return res, nil
 }
 
@@ -800,12 +1475,125 @@ func (s *Server) completionItemResolve(ctx context.Context, conn *Conn, req *jso
 		return nil, err
 	}
 
+	data, deferred, ok := s.decodeData(p.Data)
+	if ok && !deferred {
+		return p, nil
+	}
+	if ok {
+		p.Data = data
+	}
+
 	res, err := s.OnCompletionItemResolve(ctx, conn, p)
 	if err != nil {
 		return nil, err
 	}
 
-	return res, nil
+	if ok {
+		if res.Data, err = s.encodeData(res.Data, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.adaptCompletionItem(res), nil
+}
+
+// resolveData is the envelope of the opaque data which keeps its Go type across the requests,
+// like CompletionItem.Data across completionItem/resolve.
+type resolveData struct {
+	GoType   string          `json:"goType"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Deferred bool            `json:"deferred,omitempty"`
+}
+
+// encodeData wraps data into resolveData remembering its type.
+// deferred tells whether the owner of data needs to be resolved.
+func (s *Server) encodeData(data interface{}, deferred bool) (interface{}, error) {
+	d := resolveData{Deferred: deferred}
+
+	if data != nil {
+		t := reflect.TypeOf(data)
+		d.GoType = typeName(t)
+		s.dataTypes.Store(d.GoType, t)
+
+		v, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		d.Value = v
+	}
+
+	return d, nil
+}
+
+// decodeData decodes the data wrapped by encodeData into the original Go type.
+// It returns false if data is not wrapped by this server.
+func (s *Server) decodeData(data interface{}) (interface{}, bool, bool) {
+	if data == nil {
+		return nil, false, false
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, false, false
+	}
+
+	d := resolveData{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return nil, false, false
+	}
+
+	if d.GoType == "" {
+		return nil, d.Deferred, d.Value == nil
+	}
+
+	t, ok := s.dataTypes.Load(d.GoType)
+	if !ok {
+		return nil, false, false
+	}
+
+	v := reflect.New(t.(reflect.Type))
+	if err := json.Unmarshal(d.Value, v.Interface()); err != nil {
+		return nil, false, false
+	}
+
+	return v.Elem().Interface(), d.Deferred, true
+}
+
+// encodeItemData wraps the data of an item which is sent back by the client in the following requests.
+func (s *Server) encodeItemData(data interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return s.encodeData(data, false)
+}
+
+// decodeItemData decodes the data wrapped by encodeItemData, or returns data as is if it is not wrapped.
+func (s *Server) decodeItemData(data interface{}) interface{} {
+	if v, _, ok := s.decodeData(data); ok {
+		return v
+	}
+	return data
+}
+
+func containsString(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		return "*" + typeName(t.Elem())
+	}
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
 }
 
 func (s *Server) hover(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -831,7 +1619,16 @@ func (s *Server) hover(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	h := *res
+	if caps := s.textDocumentClientCapabilities().Hover; caps != nil {
+		h = h.withContentFormat(caps.ContentFormat)
+	}
+
+	return &h, nil
 }
 
 func (s *Server) signatureHelp(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -857,7 +1654,22 @@ func (s *Server) signatureHelp(ctx context.Context, conn *Conn, req *jsonrpc2.Re
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	var formats []MarkupKind
+	if caps := s.textDocumentClientCapabilities().SignatureHelp; caps != nil && caps.SignatureInformation != nil {
+		formats = caps.SignatureInformation.DocumentationFormat
+	}
+
+	sh := *res
+	sh.Signatures = make([]SignatureInformation, 0, len(res.Signatures))
+	for _, sig := range res.Signatures {
+		sh.Signatures = append(sh.Signatures, sig.withDocumentationFormat(formats))
+	}
+
+	return &sh, nil
 }
 
 func (s *Server) declaration(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -883,7 +1695,14 @@ func (s *Server) declaration(ctx context.Context, conn *Conn, req *jsonrpc2.Requ
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	caps := s.textDocumentClientCapabilities().Declaration
+	r := res.WithLinkSupport(caps != nil && caps.LinkSupport)
+
+	return &r, nil
 }
 
 func (s *Server) definition(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -909,7 +1728,14 @@ func (s *Server) definition(ctx context.Context, conn *Conn, req *jsonrpc2.Reque
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	caps := s.textDocumentClientCapabilities().Definition
+	r := res.WithLinkSupport(caps != nil && caps.LinkSupport)
+
+	return &r, nil
 }
 
 func (s *Server) typeDefinition(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -935,7 +1761,14 @@ func (s *Server) typeDefinition(ctx context.Context, conn *Conn, req *jsonrpc2.R
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	caps := s.textDocumentClientCapabilities().TypeDefinition
+	r := res.WithLinkSupport(caps != nil && caps.LinkSupport)
+
+	return &r, nil
 }
 
 func (s *Server) implementation(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -961,7 +1794,14 @@ func (s *Server) implementation(ctx context.Context, conn *Conn, req *jsonrpc2.R
 		return nil, err
 	}
 
-	return res, nil
+	if res == nil {
+		return nil, nil
+	}
+
+	caps := s.textDocumentClientCapabilities().Implementation
+	r := res.WithLinkSupport(caps != nil && caps.LinkSupport)
+
+	return &r, nil
 }
 
 func (s *Server) references(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -1039,7 +1879,9 @@ func (s *Server) documentSymbol(ctx context.Context, conn *Conn, req *jsonrpc2.R
 		return nil, err
 	}
 
-	return res, nil
+	r := NewDocumentSymbolResult(p.TextDocument.URI, res, s.textDocumentClientCapabilities().DocumentSymbol)
+
+	return &r, nil
 }
 
 func (s *Server) codeAction(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
@@ -1065,6 +1907,88 @@ func (s *Server) codeAction(ctx context.Context, conn *Conn, req *jsonrpc2.Reque
 		return nil, err
 	}
 
+	if res == nil {
+		return nil, nil
+	}
+
+	if s.OnCodeActionResolve == nil {
+		return res.Filter(p.Context.Only, s.textDocumentClientCapabilities().CodeAction), nil
+	}
+
+	// the edit can be resolved later only if the client supports, keeping the data
+	caps := s.textDocumentClientCapabilities().CodeAction
+	lazy := caps != nil && caps.DataSupport && caps.ResolveSupport != nil &&
+		containsString(caps.ResolveSupport.Properties, "edit")
+
+	if !lazy {
+		// resolve only the code actions left by the kinds, the edits are needed to adapt them
+		res = res.filterKinds(p.Context.Only, caps)
+		resolved := make(CodeActionResult, 0, len(res))
+		for _, e := range res {
+			if e.CodeAction != nil && e.CodeAction.deferred() {
+				a, err := s.OnCodeActionResolve(ctx, conn, *e.CodeAction)
+				if err != nil {
+					return nil, err
+				}
+				e = CodeActionOrCommand{CodeAction: &a}
+			}
+			resolved = append(resolved, e)
+		}
+
+		return resolved.Filter(p.Context.Only, caps), nil
+	}
+
+	res = res.Filter(p.Context.Only, caps)
+	for _, e := range res {
+		if e.CodeAction == nil {
+			continue
+		}
+
+		if e.CodeAction.Data, err = s.encodeData(e.CodeAction.Data, e.CodeAction.deferred()); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Server) codeActionResolve(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnCodeActionResolve == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := CodeAction{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	data, deferred, ok := s.decodeData(p.Data)
+	if ok && !deferred {
+		return p, nil
+	}
+	if ok {
+		p.Data = data
+	}
+
+	res, err := s.OnCodeActionResolve(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		if res.Data, err = s.encodeData(res.Data, false); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
@@ -1405,3 +2329,864 @@ func (s *Server) selectionRange(ctx context.Context, conn *Conn, req *jsonrpc2.R
 
 	return res, nil
 }
+
+func (s *Server) linkedEditingRange(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnLinkedEditingRange == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := LinkedEditingRangeParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnLinkedEditingRange(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) moniker(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnMoniker == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := MonikerParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnMoniker(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) inlineValue(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnInlineValue == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := InlineValueParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnInlineValue(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) inlineCompletion(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnInlineCompletion == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := InlineCompletionParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnInlineCompletion(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) semanticTokensFull(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnSemanticTokensFull == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := SemanticTokensParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnSemanticTokensFull(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return s.storeSemanticTokens(p.TextDocument.URI, *res), nil
+}
+
+// semanticTokensFullDelta computes the delta from the previous result of OnSemanticTokensFull.
+// It returns the full tokens if the previous result is not known.
+func (s *Server) semanticTokensFullDelta(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnSemanticTokensFull == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := SemanticTokensDeltaParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnSemanticTokensFull(ctx, conn, SemanticTokensParams{
+		WorkDoneProgressParams: p.WorkDoneProgressParams,
+		PartialResultParams:    p.PartialResultParams,
+		TextDocument:           p.TextDocument,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	prev, ok := s.semanticTokens.Load(p.TextDocument.URI)
+	cur := s.storeSemanticTokens(p.TextDocument.URI, *res)
+	if !ok || prev.(SemanticTokens).ResultID != p.PreviousResultID {
+		return cur, nil
+	}
+
+	return &SemanticTokensDelta{
+		ResultID: cur.ResultID,
+		Edits:    DiffSemanticTokens(prev.(SemanticTokens).Data, cur.Data),
+	}, nil
+}
+
+// storeSemanticTokens assigns a new result ID to tokens and keeps them as the last result of the document.
+func (s *Server) storeSemanticTokens(uri DocumentURI, tokens SemanticTokens) *SemanticTokens {
+	tokens.ResultID = strconv.FormatUint(atomic.AddUint64(&s.semanticTokensID, 1), 10)
+	if tokens.Data == nil {
+		tokens.Data = []int{}
+	}
+	s.semanticTokens.Store(uri, tokens)
+
+	return &tokens
+}
+
+func (s *Server) semanticTokensRange(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnSemanticTokensRange == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := SemanticTokensRangeParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnSemanticTokensRange(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) prepareCallHierarchy(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnPrepareCallHierarchy == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := CallHierarchyPrepareParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnPrepareCallHierarchy(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	items := make([]CallHierarchyItem, 0, len(res))
+	for _, item := range res {
+		if item.Data, err = s.encodeItemData(item.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Server) incomingCalls(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnIncomingCalls == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := CallHierarchyIncomingCallsParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+	p.Item.Data = s.decodeItemData(p.Item.Data)
+
+	res, err := s.OnIncomingCalls(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	calls := make([]CallHierarchyIncomingCall, 0, len(res))
+	for _, call := range res {
+		if call.From.Data, err = s.encodeItemData(call.From.Data); err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+func (s *Server) outgoingCalls(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnOutgoingCalls == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := CallHierarchyOutgoingCallsParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+	p.Item.Data = s.decodeItemData(p.Item.Data)
+
+	res, err := s.OnOutgoingCalls(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	calls := make([]CallHierarchyOutgoingCall, 0, len(res))
+	for _, call := range res {
+		if call.To.Data, err = s.encodeItemData(call.To.Data); err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+func (s *Server) prepareTypeHierarchy(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnPrepareTypeHierarchy == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := TypeHierarchyPrepareParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnPrepareTypeHierarchy(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	items := make([]TypeHierarchyItem, 0, len(res))
+	for _, item := range res {
+		if item.Data, err = s.encodeItemData(item.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Server) supertypes(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnSupertypes == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := TypeHierarchySupertypesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+	p.Item.Data = s.decodeItemData(p.Item.Data)
+
+	res, err := s.OnSupertypes(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	items := make([]TypeHierarchyItem, 0, len(res))
+	for _, item := range res {
+		if item.Data, err = s.encodeItemData(item.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Server) subtypes(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnSubtypes == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := TypeHierarchySubtypesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+	p.Item.Data = s.decodeItemData(p.Item.Data)
+
+	res, err := s.OnSubtypes(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	items := make([]TypeHierarchyItem, 0, len(res))
+	for _, item := range res {
+		if item.Data, err = s.encodeItemData(item.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Server) inlayHint(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnInlayHint == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := InlayHintParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnInlayHint(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	hints := make([]InlayHint, 0, len(res))
+	for _, h := range res {
+		if h.Data, err = s.encodeItemData(h.Data); err != nil {
+			return nil, err
+		}
+		hints = append(hints, h)
+	}
+
+	return hints, nil
+}
+
+func (s *Server) inlayHintResolve(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnInlayHintResolve == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := InlayHint{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+	p.Data = s.decodeItemData(p.Data)
+
+	res, err := s.OnInlayHintResolve(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Data, err = s.encodeItemData(res.Data); err != nil {
+		return nil, err
+	}
+
+	// the label is marshaled by the pointer receiver
+	return &res, nil
+}
+
+func (s *Server) documentDiagnostic(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnDocumentDiagnostic == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := DocumentDiagnosticParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnDocumentDiagnostic(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) workspaceDiagnostic(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnWorkspaceDiagnostic == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := WorkspaceDiagnosticParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	res, err := s.OnWorkspaceDiagnostic(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) willCreateFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnWillCreateFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := CreateFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].createFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	res, err := s.OnWillCreateFiles(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) didCreateFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if s.OnDidCreateFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := CreateFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].createFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	if err := s.OnDidCreateFiles(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) willRenameFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnWillRenameFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := RenameFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].renameFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	res, err := s.OnWillRenameFiles(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) didRenameFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if s.OnDidRenameFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := RenameFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].renameFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	if err := s.OnDidRenameFiles(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *Server) willDeleteFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		return err, nil
+	}
+
+	if s.OnWillDeleteFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, createError(jsonrpc2.CodeInvalidParams, "", nil)
+	}
+
+	p := DeleteFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].deleteFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	res, err := s.OnWillDeleteFiles(ctx, conn, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Server) didDeleteFiles(ctx context.Context, conn *Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if err := s.checkState(); err != nil {
+		// the notification should ignore the state error
+		return nil, nil
+	}
+
+	if s.OnDidDeleteFiles == nil {
+		return nil, nil
+	}
+
+	if req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams}
+	}
+
+	p := DeleteFilesParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Files = s.fileOperationFilters[req.Method].deleteFiles(p.Files); len(p.Files) == 0 {
+		return nil, nil
+	}
+
+	if err := s.OnDidDeleteFiles(ctx, conn, p); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// compileFileOperationFilters compiles the filters of the file operations registered with caps.
+func (s *Server) compileFileOperationFilters(caps ServerCapabilities) {
+	opts := FileOperationOptions{}
+	if caps.Workspace != nil && caps.Workspace.FileOperations != nil {
+		opts = *caps.Workspace.FileOperations
+	}
+
+	s.fileOperationFilters = map[string]fileOperationFilters{
+		"workspace/didCreateFiles":  newFileOperationFilters(opts.DidCreate),
+		"workspace/willCreateFiles": newFileOperationFilters(opts.WillCreate),
+		"workspace/didRenameFiles":  newFileOperationFilters(opts.DidRename),
+		"workspace/willRenameFiles": newFileOperationFilters(opts.WillRename),
+		"workspace/didDeleteFiles":  newFileOperationFilters(opts.DidDelete),
+		"workspace/willDeleteFiles": newFileOperationFilters(opts.WillDelete),
+	}
+}
+
+// fileOperationFilters is the compiled filters which a file operation is registered with.
+// The nil fileOperationFilters accepts all the files as the operation is not registered.
+type fileOperationFilters []fileOperationMatcher
+
+func newFileOperationFilters(opts *FileOperationRegistrationOptions) fileOperationFilters {
+	if opts == nil {
+		return nil
+	}
+
+	fs := fileOperationFilters{}
+	for _, f := range opts.Filters {
+		m, err := f.compile()
+		if err != nil {
+			// the invalid pattern matches nothing
+			continue
+		}
+		fs = append(fs, m)
+	}
+	return fs
+}
+
+// match reports whether the operation on the file or folder at uri is accepted.
+// The kinds of the filters are ignored if kind is unknown.
+func (fs fileOperationFilters) match(uri string, kind FileOperationPatternKind) bool {
+	if fs == nil {
+		return true
+	}
+
+	for _, m := range fs {
+		if m.match(uri, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fs fileOperationFilters) createFiles(files []FileCreate) []FileCreate {
+	res := []FileCreate{}
+	for _, f := range files {
+		if fs.match(f.URI, fileOperationKind(f.URI)) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// renameFiles filters files by their old URIs.
+// The kinds are looked up by the new URIs as well since the old ones do not exist after renamed.
+func (fs fileOperationFilters) renameFiles(files []FileRename) []FileRename {
+	res := []FileRename{}
+	for _, f := range files {
+		if fs.match(f.OldURI, fileOperationKind(f.OldURI, f.NewURI)) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func (fs fileOperationFilters) deleteFiles(files []FileDelete) []FileDelete {
+	res := []FileDelete{}
+	for _, f := range files {
+		if fs.match(f.URI, fileOperationKind(f.URI)) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// fileOperationKind returns the kind of the resource at the first of uris which exists.
+// The URI ending with '/' is a folder, and the kind is unknown if none of uris exists.
+func fileOperationKind(uris ...string) FileOperationPatternKind {
+	for _, uri := range uris {
+		if strings.HasSuffix(uri, "/") {
+			return FileOperationPatternKindFolder
+		}
+
+		u, err := url.Parse(uri)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+
+		fi, err := os.Stat(filepath.FromSlash(u.Path))
+		if err != nil {
+			continue
+		}
+		if fi.IsDir() {
+			return FileOperationPatternKindFolder
+		}
+		return FileOperationPatternKindFile
+	}
+
+	return ""
+}
@@ -0,0 +1,1297 @@
+package lsp_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/tennashi/lsp"
+)
+
+// clientHandler handles the requests and the notifications from the server in the tests.
+type clientHandler func(ctx context.Context, req *jsonrpc2.Request) (interface{}, error)
+
+// startServer connects a client to s and initializes it with caps.
+// It returns the client side connection and the result of initialize.
+func startServer(t *testing.T, s *lsp.Server, caps lsp.ClientCapabilities, h clientHandler) (*jsonrpc2.Conn, lsp.InitializeResult) {
+	t.Helper()
+
+	return startServerWithParams(t, s, lsp.InitializeParams{Capabilities: caps}, h)
+}
+
+// startServerWithParams is startServer initializing s with p.
+func startServerWithParams(t *testing.T, s *lsp.Server, p lsp.InitializeParams, h clientHandler) (*jsonrpc2.Conn, lsp.InitializeResult) {
+	t.Helper()
+
+	if h == nil {
+		h = func(context.Context, *jsonrpc2.Request) (interface{}, error) {
+			return nil, nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sc, cc := connPair(t)
+
+	go s.ServeStream(ctx, jsonrpc2.NewBufferedStream(sc, jsonrpc2.VSCodeObjectCodec{}))
+
+	c := jsonrpc2.NewConn(
+		ctx,
+		jsonrpc2.NewBufferedStream(cc, jsonrpc2.VSCodeObjectCodec{}),
+		jsonrpc2.HandlerWithError(func(ctx context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+			return h(ctx, req)
+		}),
+	)
+	t.Cleanup(func() {
+		c.Close()
+		cancel()
+	})
+
+	res := lsp.InitializeResult{}
+	if err := call(c, "initialize", &p, &res); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if err := c.Notify(context.Background(), "initialized", struct{}{}); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+
+	return c, res
+}
+
+// connPair returns a pair of the connections connected to each other.
+// Unlike net.Pipe, the writes are buffered not to deadlock when both sides write at once.
+func connPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	defer l.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- c
+	}()
+
+	cc, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	sc := <-accepted
+	if sc == nil {
+		t.Fatalf("failed to accept the connection")
+	}
+
+	return sc, cc
+}
+
+// call calls method with the timeout not to hang the tests.
+func call(c *jsonrpc2.Conn, method string, params, result interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return c.Call(ctx, method, params, result)
+}
+
+// receive waits for a value from ch with the timeout not to hang the tests.
+func receive(t *testing.T, ch <-chan interface{}) interface{} {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out")
+		return nil
+	}
+}
+
+func TestServer_ExecuteCommand_ApplyEdit(t *testing.T) {
+	edit := lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+			"file:///main.go": {{NewText: "package main"}},
+		},
+	}
+
+	cases := []struct {
+		applied  bool
+		command  *lsp.Command
+		executed []string
+	}{
+		{
+			applied:  true,
+			executed: []string{},
+		},
+		{
+			applied:  true,
+			command:  &lsp.Command{Title: "organize", Command: "organize"},
+			executed: []string{"organize"},
+		},
+		{
+			applied:  false,
+			command:  &lsp.Command{Title: "organize", Command: "organize"},
+			executed: []string{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			executed := make(chan interface{}, 1)
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{CodeActionProvider: &lsp.CodeActionOptions{}},
+				OnExecuteCommand: func(_ context.Context, _ *lsp.Conn, p lsp.ExecuteCommandParams) (interface{}, error) {
+					executed <- p.Command
+					return nil, nil
+				},
+			}
+
+			// the handler may outlive the subtest, so it should not refer to tt
+			ok := tt.applied
+			applied := make(chan interface{}, 1)
+			messages := make(chan interface{}, 1)
+			c, _ := startServer(t, s, lsp.ClientCapabilities{}, func(_ context.Context, req *jsonrpc2.Request) (interface{}, error) {
+				switch req.Method {
+				case "workspace/applyEdit":
+					p := lsp.ApplyWorkspaceEditParams{}
+					if err := json.Unmarshal(*req.Params, &p); err != nil {
+						return nil, err
+					}
+					applied <- p.Edit
+					return lsp.ApplyWorkspaceEditResponse{Applied: ok}, nil
+				case "window/showMessage":
+					messages <- struct{}{}
+				}
+				return nil, nil
+			})
+
+			args := []interface{}{edit}
+			if tt.command != nil {
+				args = append(args, tt.command)
+			}
+			if err := call(c, "workspace/executeCommand", &lsp.ExecuteCommandParams{
+				Command:   lsp.CodeActionApplyEditCommand,
+				Arguments: args,
+			}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			if diff := cmp.Diff(edit, receive(t, applied), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			got := []string{}
+			switch {
+			case tt.command != nil && tt.applied:
+				got = append(got, receive(t, executed).(string))
+			case !tt.applied:
+				// the failure is reported instead of executing the command
+				receive(t, messages)
+			}
+			if diff := cmp.Diff(tt.executed, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_Initialize_PositionEncoding(t *testing.T) {
+	cases := []struct {
+		preferred lsp.PositionEncoding
+		offered   []lsp.PositionEncoding
+		want      lsp.PositionEncoding
+	}{
+		{
+			want: lsp.PositionEncodingUTF16,
+		},
+		{
+			preferred: lsp.PositionEncodingUTF8,
+			want:      lsp.PositionEncodingUTF16,
+		},
+		{
+			offered: []lsp.PositionEncoding{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF16},
+			want:    lsp.PositionEncodingUTF8,
+		},
+		{
+			preferred: lsp.PositionEncodingUTF32,
+			offered:   []lsp.PositionEncoding{lsp.PositionEncodingUTF8, lsp.PositionEncodingUTF32},
+			want:      lsp.PositionEncodingUTF32,
+		},
+		{
+			preferred: lsp.PositionEncodingUTF32,
+			offered:   []lsp.PositionEncoding{"utf-7", lsp.PositionEncodingUTF16},
+			want:      lsp.PositionEncodingUTF16,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			docs := &lsp.TextDocumentStore{}
+			var enc lsp.PositionEncoding
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{PositionEncoding: tt.preferred},
+				Documents:    docs,
+				OnHover: func(_ context.Context, conn *lsp.Conn, _ lsp.HoverParams) (*lsp.Hover, error) {
+					enc = conn.PositionEncoding()
+					return nil, nil
+				},
+			}
+
+			c, res := startServer(t, s, lsp.ClientCapabilities{
+				General: &lsp.GeneralClientCapabilities{PositionEncodings: tt.offered},
+			}, nil)
+
+			if diff := cmp.Diff(tt.want, res.Capabilities.PositionEncoding); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, docs.Encoding); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := call(c, "textDocument/hover", &lsp.HoverParams{}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, enc); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_Completion_SnippetSupport(t *testing.T) {
+	cases := []struct {
+		item lsp.CompletionItem
+		want lsp.CompletionItem
+	}{
+		{
+			item: lsp.CompletionItem{
+				Label:            "func",
+				InsertText:       "func ${1:name}() {\n\t$0\n}",
+				InsertTextFormat: lsp.InsertTextFormatSnippet,
+			},
+			want: lsp.CompletionItem{
+				Label:            "func",
+				InsertText:       "func name() {\n\t\n}",
+				InsertTextFormat: lsp.InsertTextFormatPlainText,
+			},
+		},
+		{
+			item: lsp.CompletionItem{
+				Label:            "func",
+				InsertText:       "func ${1:name",
+				InsertTextFormat: lsp.InsertTextFormatSnippet,
+				TextEdit:         &lsp.TextEdit{NewText: "func ${1:name}"},
+			},
+			want: lsp.CompletionItem{
+				Label:            "func",
+				InsertText:       "func",
+				InsertTextFormat: lsp.InsertTextFormatPlainText,
+				TextEdit:         &lsp.TextEdit{NewText: "func name"},
+			},
+		},
+		{
+			item: lsp.CompletionItem{
+				Label:            "name",
+				InsertTextFormat: lsp.InsertTextFormatSnippet,
+				TextEdit:         &lsp.TextEdit{Range: lineRange(0, 0, 4), NewText: "${1:name"},
+			},
+			want: lsp.CompletionItem{
+				Label:            "name",
+				InsertTextFormat: lsp.InsertTextFormatPlainText,
+				TextEdit:         &lsp.TextEdit{Range: lineRange(0, 0, 4), NewText: "name"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{CompletionProvider: &lsp.CompletionOptions{}},
+				OnCompletion: func(context.Context, *lsp.Conn, lsp.CompletionParams) (lsp.CompletionList, error) {
+					return lsp.CompletionList{Items: []lsp.CompletionItem{tt.item}}, nil
+				},
+			}
+			c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+			got := lsp.CompletionList{}
+			if err := call(c, "textDocument/completion", &lsp.CompletionParams{}, &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff([]lsp.CompletionItem{tt.want}, got.Items, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_Hover_ContentFormat(t *testing.T) {
+	plainText := lsp.MarkupUnion{
+		MarkupContent: &lsp.MarkupContent{Kind: lsp.MarkupKindPlainText, Value: "Use foo_bar."},
+	}
+
+	cases := []struct {
+		contents lsp.MarkupUnion
+		formats  []lsp.MarkupKind
+		want     lsp.MarkupUnion
+	}{
+		{
+			// the string is a markdown MarkedString
+			contents: lsp.MarkupUnion{String: strPtr("Use `foo\\_bar`.")},
+			formats:  []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want:     plainText,
+		},
+		{
+			contents: lsp.MarkupUnion{MarkedString: &lsp.MarkedString{Value: "Use `foo\\_bar`."}},
+			formats:  []lsp.MarkupKind{lsp.MarkupKindPlainText},
+			want:     plainText,
+		},
+		{
+			contents: lsp.MarkupUnion{String: strPtr("Use `foo\\_bar`.")},
+			formats:  []lsp.MarkupKind{lsp.MarkupKindMarkdown},
+			want:     lsp.MarkupUnion{String: strPtr("Use `foo\\_bar`.")},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.Server{
+				OnHover: func(context.Context, *lsp.Conn, lsp.HoverParams) (*lsp.Hover, error) {
+					return &lsp.Hover{Contents: tt.contents}, nil
+				},
+			}
+			c, _ := startServer(t, s, lsp.ClientCapabilities{
+				TextDocument: &lsp.TextDocumentClientCapabilities{
+					Hover: &lsp.HoverClientCapabilities{ContentFormat: tt.formats},
+				},
+			}, nil)
+
+			got := lsp.Hover{}
+			if err := call(c, "textDocument/hover", &lsp.HoverParams{}, &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got.Contents, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_DidChangeTextDocument_CompletionCache(t *testing.T) {
+	cache := &lsp.CompletionCache{}
+	cache.Put("file:///main.go", 1, lsp.Position{}, "f", []lsp.CompletionItem{{Label: "foo"}})
+
+	changed := make(chan interface{}, 1)
+	s := &lsp.Server{
+		// the document is not opened, so the store fails to change it
+		Documents:       &lsp.TextDocumentStore{},
+		CompletionCache: cache,
+		OnDidChangeTextDocument: func(_ context.Context, _ *lsp.Conn, p lsp.DidChangeTextDocumentParams) error {
+			changed <- p.TextDocument.URI
+			return nil
+		},
+	}
+	messages := make(chan interface{}, 1)
+	c, _ := startServer(t, s, lsp.ClientCapabilities{}, func(_ context.Context, req *jsonrpc2.Request) (interface{}, error) {
+		if req.Method == "window/showMessage" {
+			messages <- struct{}{}
+		}
+		return nil, nil
+	})
+
+	if err := c.Notify(context.Background(), "textDocument/didChange", &lsp.DidChangeTextDocumentParams{
+		TextDocument: lsp.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///main.go"},
+			Version:                intPtr(2),
+		},
+		ContentChanges: []lsp.TextDocumentContentChangeEvent{{Text: "package main\n"}},
+	}); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	// the failure is shown instead of calling the handler
+	receive(t, messages)
+	select {
+	case <-changed:
+		t.Fatalf("the handler should not be called")
+	default:
+	}
+
+	if _, ok := cache.Get("file:///main.go", 1, lsp.Position{}, "f"); ok {
+		t.Fatalf("should be invalidated but not")
+	}
+}
+
+type testResolveData struct {
+	ID int `json:"id"`
+}
+
+func TestServer_CompletionItemResolve(t *testing.T) {
+	resolveSupport := func(props ...string) lsp.ClientCapabilities {
+		caps := lsp.ClientCapabilities{}
+		if err := json.Unmarshal([]byte(`{"textDocument":{"completion":{"completionItem":{"resolveSupport":{"properties":[]}}}}}`), &caps); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+		caps.TextDocument.Completion.CompletionItem.ResolveSupport.Properties = props
+		return caps
+	}
+	doc := &lsp.MarkupUnion{MarkupContent: &lsp.MarkupContent{Kind: lsp.MarkupKindPlainText, Value: "doc"}}
+
+	cases := []struct {
+		caps     lsp.ClientCapabilities
+		item     lsp.CompletionItem
+		resolved []interface{}
+	}{
+		{
+			item:     lsp.CompletionItem{Label: "foo", Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			item:     lsp.CompletionItem{Label: "foo"},
+			resolved: []interface{}{nil},
+		},
+		{
+			item:     lsp.CompletionItem{Label: "foo", Detail: "detail", Documentation: doc, Data: testResolveData{ID: 1}},
+			resolved: []interface{}{},
+		},
+		{
+			caps:     resolveSupport("additionalTextEdits"),
+			item:     lsp.CompletionItem{Label: "foo", Detail: "detail", Documentation: doc, Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			caps:     resolveSupport("documentation"),
+			item:     lsp.CompletionItem{Label: "foo", Documentation: doc, Data: testResolveData{ID: 1}},
+			resolved: []interface{}{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			resolved := []interface{}{}
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{CompletionProvider: &lsp.CompletionOptions{}},
+				OnCompletion: func(context.Context, *lsp.Conn, lsp.CompletionParams) (lsp.CompletionList, error) {
+					return lsp.CompletionList{Items: []lsp.CompletionItem{tt.item}}, nil
+				},
+				OnCompletionItemResolve: func(_ context.Context, _ *lsp.Conn, item lsp.CompletionItem) (lsp.CompletionItem, error) {
+					resolved = append(resolved, item.Data)
+					item.Detail = "resolved"
+					return item, nil
+				},
+			}
+			c, _ := startServer(t, s, tt.caps, nil)
+
+			list := lsp.CompletionList{}
+			if err := call(c, "textDocument/completion", &lsp.CompletionParams{}, &list); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			got := lsp.CompletionItem{}
+			if err := call(c, "completionItem/resolve", &list.Items[0], &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.resolved, resolved); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if len(tt.resolved) > 0 && got.Detail != "resolved" {
+				t.Fatalf("should be resolved but not: %+v", got)
+			}
+		})
+	}
+}
+
+func TestServer_CompletionItemResolve_NotWrapped(t *testing.T) {
+	resolved := make(chan interface{}, 1)
+	s := &lsp.Server{
+		Capabilities: lsp.ServerCapabilities{CompletionProvider: &lsp.CompletionOptions{}},
+		OnCompletionItemResolve: func(_ context.Context, _ *lsp.Conn, item lsp.CompletionItem) (lsp.CompletionItem, error) {
+			resolved <- item.Label
+			return item, nil
+		},
+	}
+	c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+	// the item has no data, e.g. it is not created by this server
+	if err := call(c, "completionItem/resolve", &lsp.CompletionItem{Label: "foo"}, nil); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if diff := cmp.Diff("foo", receive(t, resolved)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_InlayHint(t *testing.T) {
+	label := lsp.InlayHintLabel{Parts: []lsp.InlayHintLabelPart{{Value: "x"}, {Value: ": int"}}}
+
+	resolved := make(chan interface{}, 1)
+	s := &lsp.Server{
+		Capabilities: lsp.ServerCapabilities{InlayHintProvider: &lsp.InlayHintRegistrationOptions{}},
+		OnInlayHint: func(context.Context, *lsp.Conn, lsp.InlayHintParams) ([]lsp.InlayHint, error) {
+			return []lsp.InlayHint{{Label: label, Data: testResolveData{ID: 1}}}, nil
+		},
+		OnInlayHintResolve: func(_ context.Context, _ *lsp.Conn, h lsp.InlayHint) (lsp.InlayHint, error) {
+			resolved <- h.Data
+			return h, nil
+		},
+	}
+	c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+	hints := []lsp.InlayHint{}
+	if err := call(c, "textDocument/inlayHint", &lsp.InlayHintParams{}, &hints); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if len(hints) != 1 {
+		t.Fatalf("should be 1 hint but: %d", len(hints))
+	}
+	if diff := cmp.Diff(label, hints[0].Label, cmpOpt); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+
+	got := lsp.InlayHint{}
+	if err := call(c, "inlayHint/resolve", &hints[0], &got); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if diff := cmp.Diff(testResolveData{ID: 1}, receive(t, resolved)); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(label, got.Label, cmpOpt); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_WillDeleteFiles_Filters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, name := range []string{"main.go", "README.md"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+	}
+	for _, name := range []string{"pkg", "cmd.go"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+	}
+
+	root := "file://" + filepath.ToSlash(dir)
+	files := []lsp.FileDelete{
+		{URI: root + "/main.go"},
+		{URI: root + "/README.md"},
+		{URI: root + "/pkg"},
+		{URI: root + "/cmd.go"},
+		{URI: root + "/lib/"},
+		// the kind of the resource which does not exist is unknown
+		{URI: root + "/gone.go"},
+	}
+
+	cases := []struct {
+		willDelete string
+		want       []lsp.FileDelete
+	}{
+		{
+			want: files,
+		},
+		{
+			willDelete: `{"filters":[{"scheme":"file","pattern":{"glob":"**/*.go"}}]}`,
+			want:       []lsp.FileDelete{files[0], files[3], files[5]},
+		},
+		{
+			willDelete: `{"filters":[{"pattern":{"glob":"**/{pkg,lib,*.go,*.md}","matches":"folder"}}]}`,
+			want:       []lsp.FileDelete{files[2], files[3], files[4], files[5]},
+		},
+		{
+			willDelete: `{"filters":[{"pattern":{"glob":"**/{pkg,lib,*.go,*.md}","matches":"file"}}]}`,
+			want:       []lsp.FileDelete{files[0], files[1], files[5]},
+		},
+		{
+			willDelete: `{"filters":[{"pattern":{"glob":"**/*.rs"}}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			caps := lsp.ServerCapabilities{}
+			if tt.willDelete != "" {
+				if err := json.Unmarshal([]byte(`{"workspace":{"fileOperations":{"willDelete":`+tt.willDelete+`}}}`), &caps); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+			}
+
+			var got []lsp.FileDelete
+			s := &lsp.Server{
+				// the filters are read from the capabilities sent to the client
+				OnInitialize: func(context.Context, *lsp.Conn, lsp.InitializeParams) (lsp.InitializeResult, error) {
+					return lsp.InitializeResult{Capabilities: caps}, nil
+				},
+				OnWillDeleteFiles: func(_ context.Context, _ *lsp.Conn, p lsp.DeleteFilesParams) (*lsp.WorkspaceEdit, error) {
+					got = p.Files
+					return nil, nil
+				},
+			}
+			c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+			if err := call(c, "workspace/willDeleteFiles", &lsp.DeleteFilesParams{Files: files}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_WorkDoneProgressCancel(t *testing.T) {
+	cases := []struct {
+		request *lsp.ProgressToken
+		cancel  lsp.ProgressToken
+	}{
+		{
+			cancel: testStrToken,
+		},
+		{
+			cancel: testIntToken,
+		},
+		{
+			// the token provided with the finished request
+			request: &testStrToken,
+			cancel:  testStrToken,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			cancelled := make(chan interface{}, 1)
+			s := &lsp.Server{
+				OnHover: func(context.Context, *lsp.Conn, lsp.HoverParams) (*lsp.Hover, error) {
+					return nil, nil
+				},
+				OnWorkDoneProgressCancel: func(_ context.Context, _ *lsp.Conn, p lsp.WorkDoneProgressCancelParams) error {
+					cancelled <- p.Token
+					return nil
+				},
+			}
+			c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+			if tt.request != nil {
+				p := &lsp.HoverParams{WorkDoneProgressParams: lsp.WorkDoneProgressParams{WorkDoneToken: tt.request}}
+				if err := call(c, "textDocument/hover", p, nil); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+			}
+
+			if err := c.Notify(context.Background(), "window/workDoneProgress/cancel", &lsp.WorkDoneProgressCancelParams{Token: tt.cancel}); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.cancel, receive(t, cancelled), cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// traceRecorder records the $/logTrace notifications from the server.
+type traceRecorder struct {
+	mu     sync.Mutex
+	traces []lsp.LogTraceParams
+}
+
+func (r *traceRecorder) handle(_ context.Context, req *jsonrpc2.Request) (interface{}, error) {
+	if req.Method != "$/logTrace" {
+		return nil, nil
+	}
+
+	p := lsp.LogTraceParams{}
+	if err := json.Unmarshal(*req.Params, &p); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, p)
+	return nil, nil
+}
+
+// get returns the traces whose messages contain substr.
+func (r *traceRecorder) get(substr string) []lsp.LogTraceParams {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := []lsp.LogTraceParams{}
+	for _, p := range r.traces {
+		if strings.Contains(p.Message, substr) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func TestConn_LogTrace(t *testing.T) {
+	cases := []struct {
+		initialize lsp.TraceConfig
+		setTrace   lsp.TraceConfig
+		want       []lsp.LogTraceParams
+	}{
+		{
+			want: []lsp.LogTraceParams{},
+		},
+		{
+			initialize: lsp.TraceConfigOff,
+			want:       []lsp.LogTraceParams{},
+		},
+		{
+			initialize: lsp.TraceConfigMessages,
+			want:       []lsp.LogTraceParams{{Message: "hover"}},
+		},
+		{
+			initialize: lsp.TraceConfigVerbose,
+			want:       []lsp.LogTraceParams{{Message: "hover", Verbose: "details"}},
+		},
+		{
+			initialize: lsp.TraceConfigVerbose,
+			setTrace:   lsp.TraceConfigOff,
+			want:       []lsp.LogTraceParams{},
+		},
+		{
+			initialize: lsp.TraceConfigOff,
+			setTrace:   lsp.TraceConfigMessages,
+			want:       []lsp.LogTraceParams{{Message: "hover"}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.Server{
+				OnHover: func(ctx context.Context, conn *lsp.Conn, _ lsp.HoverParams) (*lsp.Hover, error) {
+					return nil, conn.LogTrace(ctx, "hover", "details")
+				},
+			}
+			r := &traceRecorder{}
+			c, _ := startServerWithParams(t, s, lsp.InitializeParams{Trace: tt.initialize}, r.handle)
+
+			if tt.setTrace != "" {
+				if err := c.Notify(context.Background(), "$/setTrace", &lsp.SetTraceParams{Value: tt.setTrace}); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+			}
+			// the notifications are received before the response
+			if err := call(c, "textDocument/hover", &lsp.HoverParams{}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, r.get("")); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConn_Refresh(t *testing.T) {
+	cases := []struct {
+		method    string
+		workspace string
+		refresh   func(*lsp.Conn, context.Context) error
+		err       bool
+	}{
+		{
+			method:    "workspace/inlayHint/refresh",
+			workspace: `{"inlayHint":{"refreshSupport":true}}`,
+			refresh:   (*lsp.Conn).RefreshInlayHints,
+		},
+		{
+			method:    "workspace/inlayHint/refresh",
+			workspace: `{"inlayHint":{}}`,
+			refresh:   (*lsp.Conn).RefreshInlayHints,
+			err:       true,
+		},
+		{
+			method:    "workspace/diagnostic/refresh",
+			workspace: `{"diagnostics":{"refreshSupport":true}}`,
+			refresh:   (*lsp.Conn).RefreshDiagnostics,
+		},
+		{
+			method:    "workspace/diagnostic/refresh",
+			workspace: `{}`,
+			refresh:   (*lsp.Conn).RefreshDiagnostics,
+			err:       true,
+		},
+		{
+			method:    "workspace/inlineValue/refresh",
+			workspace: `{"inlineValue":{"refreshSupport":true}}`,
+			refresh:   (*lsp.Conn).RefreshInlineValues,
+		},
+		{
+			method:    "workspace/inlineValue/refresh",
+			workspace: `{"inlineValue":{"refreshSupport":false}}`,
+			refresh:   (*lsp.Conn).RefreshInlineValues,
+			err:       true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			caps := lsp.ClientCapabilities{}
+			if err := json.Unmarshal([]byte(`{"workspace":`+tt.workspace+`}`), &caps); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			// the request to the client is sent outside of the handler, which blocks reading the response
+			refreshed := make(chan interface{}, 1)
+			s := &lsp.Server{
+				OnHover: func(_ context.Context, conn *lsp.Conn, _ lsp.HoverParams) (*lsp.Hover, error) {
+					go func() { refreshed <- tt.refresh(conn, context.Background()) }()
+					return nil, nil
+				},
+			}
+			methods := make(chan interface{}, 1)
+			c, _ := startServer(t, s, caps, func(_ context.Context, req *jsonrpc2.Request) (interface{}, error) {
+				methods <- req.Method
+				return nil, nil
+			})
+
+			if err := call(c, "textDocument/hover", &lsp.HoverParams{}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			err, _ := receive(t, refreshed).(error)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("should be error but not")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.method, receive(t, methods)); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_LogTraceMessages(t *testing.T) {
+	received := "Received request 'textDocument/hover - ("
+	sending := "Sending response 'textDocument/hover - ("
+
+	// the messages and the verbose are compared by their prefixes
+	cases := []struct {
+		trace lsp.TraceConfig
+		want  []lsp.LogTraceParams
+	}{
+		{
+			trace: lsp.TraceConfigOff,
+			want:  []lsp.LogTraceParams{},
+		},
+		{
+			trace: lsp.TraceConfigMessages,
+			want:  []lsp.LogTraceParams{{Message: received}, {Message: sending}},
+		},
+		{
+			trace: lsp.TraceConfigVerbose,
+			want: []lsp.LogTraceParams{
+				{Message: received, Verbose: "Params: {"},
+				{Message: sending, Verbose: "Result: null"},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.Server{
+				LogTraceMessages: true,
+				OnHover: func(context.Context, *lsp.Conn, lsp.HoverParams) (*lsp.Hover, error) {
+					return nil, nil
+				},
+			}
+			r := &traceRecorder{}
+			c, _ := startServerWithParams(t, s, lsp.InitializeParams{Trace: tt.trace}, r.handle)
+
+			if err := call(c, "textDocument/hover", &lsp.HoverParams{}, nil); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+
+			got := r.get("textDocument/hover")
+			if len(got) != len(tt.want) {
+				t.Fatalf("want %d traces but got: %+v", len(tt.want), got)
+			}
+			for i, p := range got {
+				want := tt.want[i]
+				if !strings.HasPrefix(p.Message, want.Message) || !strings.HasPrefix(p.Verbose, want.Verbose) ||
+					(want.Verbose == "") != (p.Verbose == "") {
+					t.Fatalf("want %+v but got: %+v", want, p)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_Initialize_WorkspaceSymbolProvider(t *testing.T) {
+	cases := []struct {
+		caps lsp.ServerCapabilities
+		want *lsp.WorkspaceSymbolOptions
+	}{
+		{
+			caps: lsp.ServerCapabilities{},
+		},
+		{
+			caps: lsp.ServerCapabilities{WorkspaceSymbolProvder: true},
+			want: &lsp.WorkspaceSymbolOptions{},
+		},
+		{
+			caps: lsp.ServerCapabilities{
+				WorkspaceSymbolProvder:  true,
+				WorkspaceSymbolProvider: &lsp.WorkspaceSymbolOptions{ResolveProvider: true},
+			},
+			want: &lsp.WorkspaceSymbolOptions{ResolveProvider: true},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			_, res := startServer(t, &lsp.Server{Capabilities: tt.caps}, lsp.ClientCapabilities{}, nil)
+
+			if diff := cmp.Diff(tt.want, res.Capabilities.WorkspaceSymbolProvider); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_WorkspaceSymbol(t *testing.T) {
+	lazy := lsp.ClientCapabilities{}
+	if err := json.Unmarshal([]byte(`{"workspace":{"symbol":{"resolveSupport":{"properties":["location.range"]}}}}`), &lazy); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	rng := lineRange(1, 5, 9)
+
+	cases := []struct {
+		caps     lsp.ClientCapabilities
+		symbol   lsp.WorkspaceSymbol
+		lazy     bool
+		resolved []interface{}
+	}{
+		{
+			symbol:   lsp.WorkspaceSymbol{Name: "Server", Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			symbol:   lsp.WorkspaceSymbol{Name: "Server", Location: lsp.WorkspaceSymbolLocation{Range: &rng}},
+			resolved: []interface{}{},
+		},
+		{
+			caps:     lazy,
+			symbol:   lsp.WorkspaceSymbol{Name: "Server", Data: testResolveData{ID: 1}},
+			lazy:     true,
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			caps:     lazy,
+			symbol:   lsp.WorkspaceSymbol{Name: "Server", Location: lsp.WorkspaceSymbolLocation{Range: &rng}},
+			resolved: []interface{}{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			resolved := []interface{}{}
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{WorkspaceSymbolProvider: &lsp.WorkspaceSymbolOptions{}},
+				OnWorkspaceSymbols: func(context.Context, *lsp.Conn, lsp.WorkspaceSymbolParams) ([]lsp.WorkspaceSymbol, error) {
+					return []lsp.WorkspaceSymbol{tt.symbol}, nil
+				},
+				OnWorkspaceSymbolResolve: func(_ context.Context, _ *lsp.Conn, sym lsp.WorkspaceSymbol) (lsp.WorkspaceSymbol, error) {
+					resolved = append(resolved, sym.Data)
+					sym.Location.Range = &rng
+					return sym, nil
+				},
+			}
+			c, res := startServer(t, s, tt.caps, nil)
+			if !res.Capabilities.WorkspaceSymbolProvider.ResolveProvider {
+				t.Fatalf("resolveProvider should be set")
+			}
+
+			symbols := []lsp.WorkspaceSymbol{}
+			if err := call(c, "workspace/symbol", &lsp.WorkspaceSymbolParams{}, &symbols); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if got := symbols[0].Location.Range == nil; got != tt.lazy {
+				t.Fatalf("range should be left out: %v, but got: %+v", tt.lazy, symbols[0])
+			}
+
+			got := symbols[0]
+			// the client resolves the symbols only if it supports
+			if tt.caps.Workspace != nil {
+				if err := call(c, "workspaceSymbol/resolve", &symbols[0], &got); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+			}
+			if diff := cmp.Diff(&rng, got.Location.Range); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.resolved, resolved); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_WorkspaceSymbol_NoResolve(t *testing.T) {
+	lazy := lsp.ClientCapabilities{}
+	if err := json.Unmarshal([]byte(`{"workspace":{"symbol":{"resolveSupport":{"properties":["location.range"]}}}}`), &lazy); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	rng := lineRange(1, 5, 9)
+	located := lsp.WorkspaceSymbol{Name: "Server", Location: lsp.WorkspaceSymbolLocation{URI: "file:///server.go", Range: &rng}}
+
+	cases := []struct {
+		caps lsp.ClientCapabilities
+	}{
+		{},
+		{
+			// the client cannot resolve them without the resolve provider
+			caps: lazy,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{WorkspaceSymbolProvider: &lsp.WorkspaceSymbolOptions{}},
+				OnWorkspaceSymbols: func(context.Context, *lsp.Conn, lsp.WorkspaceSymbolParams) ([]lsp.WorkspaceSymbol, error) {
+					return []lsp.WorkspaceSymbol{
+						located,
+						{Name: "Conn", Location: lsp.WorkspaceSymbolLocation{URI: "file:///conn.go"}},
+					}, nil
+				},
+			}
+			c, _ := startServer(t, s, tt.caps, nil)
+
+			got := []lsp.WorkspaceSymbol{}
+			if err := call(c, "workspace/symbol", &lsp.WorkspaceSymbolParams{}, &got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff([]lsp.WorkspaceSymbol{located}, got, cmpOpt); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_WorkspaceSymbol_SymbolInformation(t *testing.T) {
+	want := []lsp.SymbolInformation{{Name: "Server", Kind: lsp.SymbolKindStruct}}
+	s := &lsp.Server{
+		OnWorkspaceSymbol: func(context.Context, *lsp.Conn, lsp.WorkspaceSymbolParams) ([]lsp.SymbolInformation, error) {
+			return want, nil
+		},
+	}
+	c, _ := startServer(t, s, lsp.ClientCapabilities{}, nil)
+
+	got := []lsp.SymbolInformation{}
+	if err := call(c, "workspace/symbol", &lsp.WorkspaceSymbolParams{}, &got); err != nil {
+		t.Fatalf("should not be error but: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServer_CodeAction_Resolve(t *testing.T) {
+	codeActionCaps := func(dataSupport bool, props ...string) lsp.ClientCapabilities {
+		b, err := json.Marshal(props)
+		if err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+
+		caps := lsp.ClientCapabilities{}
+		if err := json.Unmarshal([]byte(`{"textDocument":{"codeAction":{
+			"codeActionLiteralSupport":{"codeActionKind":{"valueSet":[]}},
+			"dataSupport":`+strconv.FormatBool(dataSupport)+`,
+			"resolveSupport":{"properties":`+string(b)+`}}}}`), &caps); err != nil {
+			t.Fatalf("should not be error but: %v", err)
+		}
+		return caps
+	}
+	edit := &lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+			"file:///main.go": {{NewText: "package main"}},
+		},
+	}
+	command := &lsp.Command{Title: "organize", Command: "organize"}
+
+	cases := []struct {
+		caps     lsp.ClientCapabilities
+		action   lsp.CodeAction
+		lazy     bool
+		resolved []interface{}
+	}{
+		{
+			caps:     codeActionCaps(false),
+			action:   lsp.CodeAction{Title: "fix", Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			caps:     codeActionCaps(false),
+			action:   lsp.CodeAction{Title: "organize", Command: command},
+			resolved: []interface{}{},
+		},
+		{
+			caps:     codeActionCaps(true, "edit"),
+			action:   lsp.CodeAction{Title: "fix", Data: testResolveData{ID: 1}},
+			lazy:     true,
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			caps:     codeActionCaps(true, "edit"),
+			action:   lsp.CodeAction{Title: "organize", Command: command},
+			lazy:     true,
+			resolved: []interface{}{},
+		},
+		{
+			caps:     codeActionCaps(true, "edit"),
+			action:   lsp.CodeAction{Title: "fix", Edit: edit},
+			lazy:     true,
+			resolved: []interface{}{},
+		},
+		{
+			// the edit is not listed in the properties
+			caps:     codeActionCaps(true, "command"),
+			action:   lsp.CodeAction{Title: "fix", Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			// the data is not kept without dataSupport
+			caps:     codeActionCaps(false, "edit"),
+			action:   lsp.CodeAction{Title: "fix", Data: testResolveData{ID: 1}},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			resolved := []interface{}{}
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{CodeActionProvider: &lsp.CodeActionOptions{}},
+				OnCodeAction: func(context.Context, *lsp.Conn, lsp.CodeActionParams) (lsp.CodeActionResult, error) {
+					return lsp.CodeActionResult{{CodeAction: &tt.action}}, nil
+				},
+				OnCodeActionResolve: func(_ context.Context, _ *lsp.Conn, a lsp.CodeAction) (lsp.CodeAction, error) {
+					resolved = append(resolved, a.Data)
+					a.Edit = edit
+					return a, nil
+				},
+			}
+			c, res := startServer(t, s, tt.caps, nil)
+			if !res.Capabilities.CodeActionProvider.ResolveProvider {
+				t.Fatalf("resolveProvider should be set")
+			}
+
+			actions := lsp.CodeActionResult{}
+			if err := call(c, "textDocument/codeAction", &lsp.CodeActionParams{}, &actions); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			got := *actions[0].CodeAction
+
+			if tt.lazy {
+				// the client resolves the actions when one is chosen
+				a := got
+				got = lsp.CodeAction{}
+				if err := call(c, "codeAction/resolve", &a, &got); err != nil {
+					t.Fatalf("should not be error but: %v", err)
+				}
+			}
+
+			if diff := cmp.Diff(tt.resolved, resolved); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+			if tt.action.Command == nil {
+				if diff := cmp.Diff(edit, got.Edit); diff != "" {
+					t.Fatalf("mismatch (-want +got):\n%s", diff)
+				}
+			} else if got.Edit != nil {
+				t.Fatalf("the command only action should not be resolved: %+v", got)
+			}
+		})
+	}
+}
+
+func TestServer_CodeAction_ResolveFiltered(t *testing.T) {
+	cases := []struct {
+		caps     string
+		only     []lsp.CodeActionKind
+		resolved []interface{}
+	}{
+		{
+			caps:     `{"codeActionLiteralSupport":{"codeActionKind":{"valueSet":[]}}}`,
+			only:     []lsp.CodeActionKind{lsp.CodeActionKindQuickFix},
+			resolved: []interface{}{testResolveData{ID: 1}},
+		},
+		{
+			caps:     `{"codeActionLiteralSupport":{"codeActionKind":{"valueSet":["refactor"]}}}`,
+			resolved: []interface{}{testResolveData{ID: 2}},
+		},
+		{
+			// the code actions are converted to the commands with the resolved edits
+			caps:     `{}`,
+			only:     []lsp.CodeActionKind{lsp.CodeActionKindRefactor},
+			resolved: []interface{}{testResolveData{ID: 2}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			resolved := []interface{}{}
+			s := &lsp.Server{
+				Capabilities: lsp.ServerCapabilities{CodeActionProvider: &lsp.CodeActionOptions{}},
+				OnCodeAction: func(context.Context, *lsp.Conn, lsp.CodeActionParams) (lsp.CodeActionResult, error) {
+					return lsp.CodeActionResult{
+						{CodeAction: &lsp.CodeAction{Title: "fix", Kind: lsp.CodeActionKindQuickFix, Data: testResolveData{ID: 1}}},
+						{CodeAction: &lsp.CodeAction{Title: "extract", Kind: lsp.CodeActionKindRefactor, Data: testResolveData{ID: 2}}},
+					}, nil
+				},
+				OnCodeActionResolve: func(_ context.Context, _ *lsp.Conn, a lsp.CodeAction) (lsp.CodeAction, error) {
+					resolved = append(resolved, a.Data)
+					a.Edit = &lsp.WorkspaceEdit{}
+					return a, nil
+				},
+			}
+
+			caps := lsp.ClientCapabilities{}
+			if err := json.Unmarshal([]byte(`{"textDocument":{"codeAction":`+tt.caps+`}}`), &caps); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			c, _ := startServer(t, s, caps, nil)
+
+			actions := lsp.CodeActionResult{}
+			if err := call(c, "textDocument/codeAction", &lsp.CodeActionParams{
+				Context: lsp.CodeActionContext{Only: tt.only},
+			}, &actions); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if len(actions) != 1 {
+				t.Fatalf("only one action should be returned but: %+v", actions)
+			}
+
+			if diff := cmp.Diff(tt.resolved, resolved); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
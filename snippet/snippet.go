@@ -0,0 +1,424 @@
+// Package snippet builds and parses the snippet syntax of the completion items.
+package snippet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Builder builds a snippet escaping the texts.
+// The zero value is ready to use.
+type Builder struct {
+	b strings.Builder
+}
+
+// Text adds s which is inserted literally.
+func (b *Builder) Text(s string) *Builder {
+	b.b.WriteString(escape(s, `\$}`))
+	return b
+}
+
+func (b *Builder) Tabstop(n int) *Builder {
+	fmt.Fprintf(&b.b, "$%d", n)
+	return b
+}
+
+// FinalTabstop adds $0, the final cursor position.
+func (b *Builder) FinalTabstop() *Builder {
+	return b.Tabstop(0)
+}
+
+// Placeholder adds the tabstop n whose default value is built by fn.
+func (b *Builder) Placeholder(n int, fn func(*Builder)) *Builder {
+	fmt.Fprintf(&b.b, "${%d:", n)
+	if fn != nil {
+		fn(b)
+	}
+	b.b.WriteString("}")
+	return b
+}
+
+func (b *Builder) Choice(n int, choices ...string) *Builder {
+	escaped := make([]string, 0, len(choices))
+	for _, c := range choices {
+		escaped = append(escaped, escape(c, `\$},|`))
+	}
+
+	fmt.Fprintf(&b.b, "${%d|%s|}", n, strings.Join(escaped, ","))
+	return b
+}
+
+// Variable adds the variable name whose default value is built by fn.
+// fn may be nil, then the variable has no default value.
+func (b *Builder) Variable(name string, fn func(*Builder)) *Builder {
+	if fn == nil {
+		fmt.Fprintf(&b.b, "${%s}", name)
+		return b
+	}
+
+	fmt.Fprintf(&b.b, "${%s:", name)
+	fn(b)
+	b.b.WriteString("}")
+	return b
+}
+
+func (b *Builder) String() string {
+	return b.b.String()
+}
+
+func escape(s, chars string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(chars, s[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Snippet is a parsed snippet.
+type Snippet struct {
+	nodes []node
+}
+
+type node interface {
+	plainText(b *strings.Builder)
+}
+
+type text struct {
+	value string
+}
+
+func (n *text) plainText(b *strings.Builder) {
+	b.WriteString(n.value)
+}
+
+type tabstop struct {
+	index     int
+	children  []node
+	choices   []string
+	transform bool
+}
+
+func (n *tabstop) plainText(b *strings.Builder) {
+	if n.transform {
+		return
+	}
+
+	if n.choices != nil {
+		b.WriteString(n.choices[0])
+		return
+	}
+
+	for _, c := range n.children {
+		c.plainText(b)
+	}
+}
+
+type variable struct {
+	name      string
+	children  []node
+	transform bool
+}
+
+func (n *variable) plainText(b *strings.Builder) {
+	if n.transform {
+		return
+	}
+
+	for _, c := range n.children {
+		c.plainText(b)
+	}
+}
+
+// PlainText returns the text to insert with the default values of the placeholders and the variables,
+// the first values of the choices.
+func (s *Snippet) PlainText() string {
+	var b strings.Builder
+	for _, n := range s.nodes {
+		n.plainText(&b)
+	}
+	return b.String()
+}
+
+// Parse parses s according to the snippet syntax.
+func Parse(s string) (*Snippet, error) {
+	p := &parser{src: s}
+
+	nodes, err := p.parseAny(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snippet{nodes: nodes}, nil
+}
+
+// Validate reports whether s is valid as a snippet.
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+// PlainText returns the plain text of the snippet s.
+func PlainText(s string) (string, error) {
+	sn, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return sn.PlainText(), nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// parseAny parses the nodes until the end of the source, or the '}' if nested.
+func (p *parser) parseAny(nested bool) ([]node, error) {
+	nodes := []node{}
+	var t strings.Builder
+
+	flush := func() {
+		if t.Len() > 0 {
+			nodes = append(nodes, &text{value: t.String()})
+			t.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '\\':
+			p.pos++
+			if n := p.peek(); n == '$' || n == '}' || n == '\\' {
+				t.WriteByte(n)
+				p.pos++
+				continue
+			}
+			t.WriteByte('\\')
+		case '}':
+			if nested {
+				flush()
+				return nodes, nil
+			}
+			// a '}' at the top level can only be a text
+			t.WriteByte(c)
+			p.pos++
+		case '$':
+			flush()
+			n, err := p.parseDollar()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		default:
+			t.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, p.errorf("missing '}'")
+	}
+	flush()
+
+	return nodes, nil
+}
+
+func (p *parser) parseDollar() (node, error) {
+	p.pos++ // '$'
+
+	if i, ok := p.parseInt(); ok {
+		return &tabstop{index: i}, nil
+	}
+	if name, ok := p.parseVar(); ok {
+		return &variable{name: name}, nil
+	}
+
+	if !p.consume('{') {
+		return nil, p.errorf("unescaped '$'")
+	}
+
+	if i, ok := p.parseInt(); ok {
+		return p.parseTabstop(i)
+	}
+	if name, ok := p.parseVar(); ok {
+		return p.parseVariable(name)
+	}
+
+	return nil, p.errorf("invalid tabstop or variable")
+}
+
+func (p *parser) parseTabstop(i int) (node, error) {
+	switch {
+	case p.consume('}'):
+		return &tabstop{index: i}, nil
+	case p.consume(':'):
+		children, err := p.parseAny(true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // '}'
+		return &tabstop{index: i, children: children}, nil
+	case p.consume('|'):
+		choices, err := p.parseChoices()
+		if err != nil {
+			return nil, err
+		}
+		return &tabstop{index: i, choices: choices}, nil
+	case p.consume('/'):
+		if err := p.skipTransform(); err != nil {
+			return nil, err
+		}
+		return &tabstop{index: i, transform: true}, nil
+	default:
+		return nil, p.errorf("invalid tabstop")
+	}
+}
+
+func (p *parser) parseChoices() ([]string, error) {
+	choices := []string{}
+	var t strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if n := p.peek(); strings.IndexByte(`\$},|`, n) >= 0 {
+				t.WriteByte(n)
+				p.pos++
+				continue
+			}
+			t.WriteByte('\\')
+		case ',':
+			choices = append(choices, t.String())
+			t.Reset()
+		case '|':
+			if !p.consume('}') {
+				return nil, p.errorf("missing '}' after choices")
+			}
+			return append(choices, t.String()), nil
+		default:
+			t.WriteByte(c)
+		}
+	}
+
+	return nil, p.errorf("missing '|}'")
+}
+
+func (p *parser) parseVariable(name string) (node, error) {
+	switch {
+	case p.consume('}'):
+		return &variable{name: name}, nil
+	case p.consume(':'):
+		children, err := p.parseAny(true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // '}'
+		return &variable{name: name, children: children}, nil
+	case p.consume('/'):
+		if err := p.skipTransform(); err != nil {
+			return nil, err
+		}
+		return &variable{name: name, transform: true}, nil
+	default:
+		return nil, p.errorf("invalid variable")
+	}
+}
+
+// skipTransform skips the regex, the format and the options of a tabstop or variable transform.
+func (p *parser) skipTransform() error {
+	for part := 0; part < 2; part++ {
+		for {
+			if p.pos >= len(p.src) {
+				return p.errorf("missing '/'")
+			}
+			c := p.src[p.pos]
+			p.pos++
+			if c == '\\' {
+				p.pos++
+				continue
+			}
+			if c == '/' {
+				break
+			}
+			// the format may contain ${1:/upcase}
+			if part == 1 && c == '$' && p.consume('{') {
+				i := strings.IndexByte(p.src[p.pos:], '}')
+				if i < 0 {
+					return p.errorf("missing '}'")
+				}
+				p.pos += i + 1
+			}
+		}
+	}
+
+	for {
+		c := p.peek()
+		if c < 'a' || c > 'z' {
+			break
+		}
+		p.pos++
+	}
+
+	if !p.consume('}') {
+		return p.errorf("missing '}'")
+	}
+	return nil
+}
+
+func (p *parser) parseInt() (int, bool) {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return i, true
+}
+
+func (p *parser) parseVar() (string, bool) {
+	isStart := func(c byte) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) || !isStart(p.src[p.pos]) {
+		return "", false
+	}
+	p.pos++
+	for p.pos < len(p.src) && (isStart(p.src[p.pos]) || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos], true
+}
@@ -0,0 +1,130 @@
+package snippet_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tennashi/lsp/snippet"
+)
+
+func TestBuilder(t *testing.T) {
+	cases := []struct {
+		build func(b *snippet.Builder)
+		want  string
+	}{
+		{
+			build: func(b *snippet.Builder) {
+				b.Text("func ").
+					Placeholder(1, func(b *snippet.Builder) { b.Text("name") }).
+					Text("(").
+					Placeholder(2, func(b *snippet.Builder) {
+						b.Text("a ").Placeholder(3, func(b *snippet.Builder) { b.Text("int") })
+					}).
+					Text(") {\n\t").
+					FinalTabstop().
+					Text("\n}")
+			},
+			want: "func ${1:name}(${2:a ${3:int}}) {\n\t$0\n\\}",
+		},
+		{
+			build: func(b *snippet.Builder) {
+				b.Text(`$x} \`).Tabstop(1)
+			},
+			want: `\$x\} \\$1`,
+		},
+		{
+			build: func(b *snippet.Builder) {
+				b.Choice(1, "a,b", "c|d", "$").Variable("TM_FILENAME", nil)
+			},
+			want: `${1|a\,b,c\|d,\$|}${TM_FILENAME}`,
+		},
+		{
+			build: func(b *snippet.Builder) {
+				b.Variable("TM_SELECTED_TEXT", func(b *snippet.Builder) { b.Tabstop(1) })
+			},
+			want: `${TM_SELECTED_TEXT:$1}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			b := &snippet.Builder{}
+			tt.build(b)
+
+			got := b.String()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := snippet.Validate(got); err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "func ${1:name}(${2:a ${3:int}}) {\n\t$0\n}",
+			want:  "func name(a int) {\n\t\n}",
+		},
+		{
+			input: `\$x\} \\ \a $1 ${2}`,
+			want:  `$x} \ \a  `,
+		},
+		{
+			input: `${1|a\,b,c|} ${TM_FILENAME:file} $TM_LINE_INDEX ${TM_FILENAME/(.*)\/x/${1:/upcase}/g}`,
+			want:  `a,b file  `,
+		},
+		{
+			input: "${1:name} ${1/(.*)/${1:/upcase}/} ${2/^(a)|b$/${1:?x:y}/gi}",
+			want:  "name  ",
+		},
+		{
+			input: "",
+			want:  "",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			got, err := snippet.PlainText(tt.input)
+			if err != nil {
+				t.Fatalf("should not be error but: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		input string
+	}{
+		{input: "$"},
+		{input: "a $ b"},
+		{input: "${1:a"},
+		{input: "${"},
+		{input: "${1"},
+		{input: "${1|a,b}"},
+		{input: "${1|a,b|"},
+		{input: "${-1}"},
+		{input: "${VAR/a/b}"},
+		{input: "${VAR/a/b/gX}"},
+		{input: "${1/a/b}"},
+	}
+
+	for _, tt := range cases {
+		t.Run("", func(t *testing.T) {
+			if err := snippet.Validate(tt.input); err == nil {
+				t.Fatalf("should be error but not")
+			}
+		})
+	}
+}